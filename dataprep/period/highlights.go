@@ -18,6 +18,12 @@ var (
 	HighlightWN8       = highlight{dataprep.TagWN8, []dataprep.Tag{dataprep.TagBattles, dataprep.TagAvgDamage, dataprep.TagWN8}, "label_highlight_wn8"}
 )
 
+var AllHighlights = []highlight{HighlightAvgDamage, HighlightBattles, HighlightWN8}
+
+func (h highlight) Label() string {
+	return h.label
+}
+
 type highlightedVehicle struct {
 	highlight highlight
 	vehicle   stats.ReducedVehicleStats
@@ -0,0 +1,8 @@
+package session
+
+type SessionAchievement struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ImageURL string `json:"image"`
+	Count    int    `json:"count"`
+}
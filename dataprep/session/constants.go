@@ -2,4 +2,12 @@ package session
 
 import "github.com/cufee/aftermath-core/dataprep"
 
+const (
+	LabelOverviewRating  = "label_overview_rating"
+	LabelOverviewUnrated = "label_overview_unrated"
+
+	LabelRatingCalibrationBattlesLeft = "label_rating_calibration_battles_left"
+	LabelRatingTopPercent             = "label_rating_top_percent"
+)
+
 var DefaultSessionBlocks = []dataprep.Tag{dataprep.TagBattles, dataprep.TagAvgDamage, dataprep.TagDamageRatio, dataprep.TagWinrate, dataprep.TagWN8}
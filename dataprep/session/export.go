@@ -8,6 +8,7 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	core "github.com/cufee/aftermath-core/internal/core/stats"
 	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
 	"golang.org/x/text/language"
 )
 
@@ -20,6 +21,10 @@ type ExportInput struct {
 
 	VehicleGlossary       map[int]models.Vehicle
 	GlobalVehicleAverages map[int]core.ReducedStatsFrame
+
+	// Optional, the rating overview card will not include a leaderboard position when this is nil
+	RatingPosition *wotblitz.PlayerLeaderboard
+	RatingSeason   *wotblitz.RatingSeason
 }
 
 type ExportOptions struct {
@@ -45,7 +50,7 @@ func SnapshotToSession(input ExportInput, options ExportOptions) (Cards, error)
 	var allBattles = input.SessionStats.Rating.Battles + input.SessionStats.Global.Battles
 
 	// Rating battles
-	if (allBattles == 0 && input.CareerStats.Rating.Battles > 0) || input.SessionStats.Rating.Battles > 0 {
+	if ShowRatingOverview(input.SessionStats, input.CareerStats) {
 		var ratingBlocks []StatsBlock
 		for _, preset := range options.Blocks {
 			if preset == dataprep.TagWN8 {
@@ -59,10 +64,11 @@ func SnapshotToSession(input ExportInput, options ExportOptions) (Cards, error)
 			ratingBlocks = append(ratingBlocks, ratingBlock)
 		}
 		cards.Rating = append(cards.Rating, Card{
-			Title:  options.LocalePrinter("label_overview_rating"),
+			Title:  options.LocalePrinter(LabelOverviewRating),
 			Blocks: ratingBlocks,
 			Type:   dataprep.CardTypeOverview,
 		})
+		cards.RatingOverview = newRatingOverview(input.RatingPosition, input.RatingSeason, options.LocalePrinter)
 	}
 
 	// Rating Vehicles
@@ -108,7 +114,7 @@ func SnapshotToSession(input ExportInput, options ExportOptions) (Cards, error)
 			unratedBlocks = append(unratedBlocks, block)
 		}
 		cards.Unrated = append(cards.Unrated, Card{
-			Title:  options.LocalePrinter("label_overview_unrated"),
+			Title:  options.LocalePrinter(LabelOverviewUnrated),
 			Blocks: unratedBlocks,
 			Type:   dataprep.CardTypeOverview,
 			Meta:   "unrated",
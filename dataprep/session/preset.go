@@ -12,6 +12,8 @@ type Card dataprep.StatsCard[StatsBlock, string]
 type Cards struct {
 	Unrated []Card `json:"unrated"`
 	Rating  []Card `json:"rating"`
+
+	RatingOverview *RatingOverview `json:"ratingOverview,omitempty"`
 }
 
 type StatsBlock struct {
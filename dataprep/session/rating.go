@@ -0,0 +1,89 @@
+package session
+
+import (
+	"fmt"
+
+	core "github.com/cufee/aftermath-core/internal/core/stats"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
+)
+
+type RatingOverview struct {
+	Score                  int     `json:"score"`
+	Position               int     `json:"position"`
+	Percentile             float64 `json:"percentile"`
+	CalibrationBattlesLeft int     `json:"calibrationBattlesLeft"`
+
+	League     string `json:"league"`
+	LeagueIcon string `json:"leagueIcon"`
+
+	Label     string           `json:"label"` // Position and percentile or calibration progress, ready to be displayed
+	Neighbors []RatingNeighbor `json:"neighbors,omitempty"`
+}
+
+type RatingNeighbor struct {
+	AccountID int    `json:"accountId"`
+	Nickname  string `json:"nickname"`
+	ClanTag   string `json:"clanTag"`
+	Position  int    `json:"position"`
+	Score     int    `json:"score"`
+}
+
+/*
+ShowRatingOverview returns true when a session will include the rating overview card
+*/
+func ShowRatingOverview(sessionStats, careerStats core.SessionSnapshot) bool {
+	allBattles := sessionStats.Rating.Battles + sessionStats.Global.Battles
+	return (allBattles == 0 && careerStats.Rating.Battles > 0) || sessionStats.Rating.Battles > 0
+}
+
+func newRatingOverview(position *wotblitz.PlayerLeaderboard, season *wotblitz.RatingSeason, printer func(string) string) *RatingOverview {
+	if position == nil {
+		return nil
+	}
+
+	overview := RatingOverview{
+		Score:                  position.Score,
+		Position:               position.Position,
+		Percentile:             percentileToPercent(position.Percentile),
+		CalibrationBattlesLeft: position.CalibrationBattlesLeft,
+	}
+	if season != nil {
+		if league, ok := season.League(position.LeagueIndex); ok {
+			overview.League = league.Title
+			overview.LeagueIcon = league.SmallIcon
+		}
+	}
+
+	switch {
+	case overview.CalibrationBattlesLeft > 0:
+		overview.Label = fmt.Sprintf("%s: %d", printer(LabelRatingCalibrationBattlesLeft), overview.CalibrationBattlesLeft)
+	case overview.Position > 0:
+		overview.Label = fmt.Sprintf("#%d", overview.Position)
+		if overview.Percentile > 0 {
+			overview.Label += fmt.Sprintf(" • %s %.1f%%", printer(LabelRatingTopPercent), overview.Percentile)
+		}
+	}
+
+	for _, neighbor := range position.Neighbors {
+		if neighbor.Skip || neighbor.Position < 1 {
+			continue
+		}
+		overview.Neighbors = append(overview.Neighbors, RatingNeighbor{
+			AccountID: neighbor.AccountID,
+			Nickname:  neighbor.Nickname,
+			ClanTag:   neighbor.ClanTag,
+			Position:  neighbor.Position,
+			Score:     neighbor.Score,
+		})
+	}
+
+	return &overview
+}
+
+// The leaderboard returns percentiles as a fraction, this makes sure we always work with percents
+func percentileToPercent(value float64) float64 {
+	if value > 0 && value <= 1 {
+		return value * 100
+	}
+	return value
+}
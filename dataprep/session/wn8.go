@@ -12,6 +12,8 @@ type SessionStats struct {
 	Clan       wg.Clan    `json:"clan"`
 	Account    wg.Account `json:"account"`
 	Cards      Cards      `json:"cards"`
+
+	Achievements []SessionAchievement `json:"achievements,omitempty"`
 }
 
 func calculateWeightedWN8(vehicles map[int]stats.ReducedVehicleStats, averages map[int]stats.ReducedStatsFrame) int {
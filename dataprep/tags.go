@@ -30,6 +30,13 @@ const (
 	TagDamageAssistedCombined Tag = "assisted_combined"
 )
 
+var AllTags = []Tag{
+	TagWN8, TagFrags, TagBattles, TagWinrate, TagAccuracy, TagRankedRating,
+	TagAvgDamage, TagDamageRatio,
+	TagAvgTier, TagSurvivalRatio, TagSurvivalPercent,
+	TagDamageDealt, TagDamageTaken, TagDamageBlocked, TagDamageAssisted, TagDamageAssistedCombined,
+}
+
 func ParseTags(tags ...string) ([]Tag, error) {
 	var parsed []Tag
 	for _, tag := range tags {
@@ -11,15 +11,17 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/utils"
 )
 
-var DefaultClient *Client
-
-func init() {
-	DefaultClient = &Client{
+/*
+DefaultClient is created from env on first use, packages importing cloudinary do not need credentials until an upload is made
+*/
+var DefaultClient = sync.OnceValue(func() *Client {
+	return &Client{
 		cloudName: utils.MustGetEnv("CLOUDINARY_API_CLOUD_NAME"),
 		apiSecret: utils.MustGetEnv("CLOUDINARY_API_SECRET"),
 		apiKey:    utils.MustGetEnv("CLOUDINARY_API_KEY"),
@@ -29,7 +31,7 @@ func init() {
 
 		signatureExclude: []string{"file", "cloud_name", "resource_type", "api_key"},
 	}
-}
+})
 
 type uploadResponse struct {
 	PublicID  string    `json:"public_id"`
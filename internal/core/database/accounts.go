@@ -1,12 +1,22 @@
 package database
 
 import (
+	"errors"
+
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var (
+	ErrAccountNotFound = errors.New("account not found")
+)
+
 func UpdatePlayerAccounts(accounts ...models.Account) error {
+	return DefaultStorage.UpdatePlayerAccounts(accounts...)
+}
+
+func (c *Client) UpdatePlayerAccounts(accounts ...models.Account) error {
 	var writes []mongo.WriteModel
 	for _, account := range accounts {
 		model := mongo.NewUpdateOneModel()
@@ -16,10 +26,44 @@ func UpdatePlayerAccounts(accounts ...models.Account) error {
 		writes = append(writes, model)
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionAccounts).BulkWrite(ctx, writes)
+	_, err := c.Collection(CollectionAccounts).BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+UpdateAccountsWN8 sets the current career WN8 for each account and appends it to the capped WN8 history.
+*/
+func UpdateAccountsWN8(values map[int]models.AccountWN8) error {
+	return DefaultStorage.UpdateAccountsWN8(values)
+}
+
+func (c *Client) UpdateAccountsWN8(values map[int]models.AccountWN8) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var writes []mongo.WriteModel
+	for id, value := range values {
+		model := mongo.NewUpdateOneModel()
+		model.SetFilter(bson.M{"_id": id})
+		model.SetUpdate(bson.M{
+			"$set":  bson.M{"wn8": value},
+			"$push": bson.M{"wn8History": bson.M{"$each": []models.AccountWN8{value}, "$slice": -models.AccountWN8HistoryLimit}},
+		})
+		writes = append(writes, model)
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionAccounts).BulkWrite(ctx, writes)
 	if err != nil {
 		return err
 	}
@@ -28,12 +72,19 @@ func UpdatePlayerAccounts(accounts ...models.Account) error {
 }
 
 func GetPlayerAccount(id int) (models.Account, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetPlayerAccount(id)
+}
+
+func (c *Client) GetPlayerAccount(id int) (models.Account, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var account models.Account
-	err := DefaultClient.Collection(CollectionAccounts).FindOne(ctx, bson.M{"_id": id}).Decode(&account)
+	err := c.Collection(CollectionAccounts).FindOne(ctx, bson.M{"_id": id}).Decode(&account)
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return account, ErrAccountNotFound
+		}
 		return account, err
 	}
 
@@ -41,10 +92,14 @@ func GetPlayerAccount(id int) (models.Account, error) {
 }
 
 func GetRealmAccountIDs(realm string) ([]int, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetRealmAccountIDs(realm)
+}
+
+func (c *Client) GetRealmAccountIDs(realm string) ([]int, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	cur, err := DefaultClient.Collection(CollectionAccounts).Find(ctx, bson.M{"realm": realm})
+	cur, err := c.Collection(CollectionAccounts).Find(ctx, bson.M{"realm": realm})
 	if err != nil {
 		return nil, err
 	}
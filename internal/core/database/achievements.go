@@ -0,0 +1,71 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrNoAchievementsSnapshot = errors.New("no achievements snapshot found")
+)
+
+func InsertAchievementsSnapshots(snapshots ...models.AchievementsSnapshot) error {
+	return DefaultStorage.InsertAchievementsSnapshots(snapshots...)
+}
+
+func (c *Client) InsertAchievementsSnapshots(snapshots ...models.AchievementsSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	var documents []interface{}
+	for _, snapshot := range snapshots {
+		documents = append(documents, snapshot)
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionAchievementsSnapshots).InsertMany(ctx, documents)
+	return err
+}
+
+/*
+GetLastAchievementsSnapshot returns the latest snapshot recorded with referenceID, an empty reference returns realm-wide snapshots
+*/
+func GetLastAchievementsSnapshot(accountID int, referenceID string) (models.AchievementsSnapshot, error) {
+	return DefaultStorage.GetLastAchievementsSnapshot(accountID, referenceID)
+}
+
+func (c *Client) GetLastAchievementsSnapshot(accountID int, referenceID string) (models.AchievementsSnapshot, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	findOptions := options.FindOne()
+	findOptions.SetSort(bson.M{"createdAt": -1})
+
+	var snapshot models.AchievementsSnapshot
+	err := c.Collection(CollectionAchievementsSnapshots).FindOne(ctx, bson.M{"accountId": accountID, "referenceId": referenceFilter(referenceID)}, findOptions).Decode(&snapshot)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return snapshot, ErrNoAchievementsSnapshot
+		}
+		return snapshot, err
+	}
+
+	return snapshot, nil
+}
+
+/*
+referenceFilter matches documents saved with referenceID, documents without a reference have the field missing
+*/
+func referenceFilter(referenceID string) any {
+	if referenceID == "" {
+		return bson.M{"$in": bson.A{"", nil}}
+	}
+	return referenceID
+}
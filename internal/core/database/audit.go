@@ -0,0 +1,71 @@
+package database
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+AddAuditLogEntries appends entries to the audit log, entries are never updated or removed
+*/
+func AddAuditLogEntries(entries ...models.AuditLogEntry) error {
+	return DefaultStorage.AddAuditLogEntries(entries...)
+}
+
+func (c *Client) AddAuditLogEntries(entries ...models.AuditLogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var documents []any
+	for _, entry := range entries {
+		documents = append(documents, entry)
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionAuditLog).InsertMany(ctx, documents)
+	return err
+}
+
+func FindAuditLogEntries(query models.AuditLogQuery) ([]models.AuditLogEntry, error) {
+	return DefaultStorage.FindAuditLogEntries(query)
+}
+
+func (c *Client) FindAuditLogEntries(query models.AuditLogQuery) ([]models.AuditLogEntry, error) {
+	filter := bson.M{}
+	if query.ActorID != "" {
+		filter["actorId"] = query.ActorID
+	}
+	if query.TargetUserID != "" {
+		filter["targetUserId"] = query.TargetUserID
+	}
+	if !query.From.IsZero() || !query.To.IsZero() {
+		createdAt := bson.M{}
+		if !query.From.IsZero() {
+			createdAt["$gte"] = query.From
+		}
+		if !query.To.IsZero() {
+			createdAt["$lte"] = query.To
+		}
+		filter["createdAt"] = createdAt
+	}
+
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var entries []models.AuditLogEntry
+	cur, err := c.Collection(CollectionAuditLog).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, cur.All(ctx, &entries)
+}
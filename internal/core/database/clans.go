@@ -0,0 +1,129 @@
+package database
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func GetClans(clanIDs ...int) (map[int]models.Clan, error) {
+	return DefaultStorage.GetClans(clanIDs...)
+}
+
+func (c *Client) GetClans(clanIDs ...int) (map[int]models.Clan, error) {
+	if len(clanIDs) == 0 {
+		return make(map[int]models.Clan), nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var clans []models.Clan
+	cur, err := c.Collection(CollectionClans).Find(ctx, bson.M{"_id": bson.M{"$in": clanIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cur.All(ctx, &clans)
+	if err != nil {
+		return nil, err
+	}
+
+	clanMap := make(map[int]models.Clan)
+	for _, clan := range clans {
+		clanMap[clan.ID] = clan
+	}
+
+	return clanMap, nil
+}
+
+/*
+GetRealmClanIDs returns IDs of all clans on a realm. Clans saved before the realm field was added are included for every realm,
+they will be skipped by the refresh on other realms and have the field set once updated on their own.
+*/
+func GetRealmClanIDs(realm string) ([]int, error) {
+	return DefaultStorage.GetRealmClanIDs(realm)
+}
+
+func (c *Client) GetRealmClanIDs(realm string) ([]int, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	result, err := c.Collection(CollectionClans).Distinct(ctx, "_id", bson.M{"realm": bson.M{"$in": bson.A{realm, "", nil}}})
+	if err != nil {
+		return nil, err
+	}
+
+	var clanIDs []int
+	for _, id := range result {
+		switch cast := id.(type) {
+		case int32:
+			clanIDs = append(clanIDs, int(cast))
+		case int64:
+			clanIDs = append(clanIDs, int(cast))
+		}
+	}
+	return clanIDs, nil
+}
+
+/*
+UpdateClans upserts all clans, createdAt is only set when a clan is inserted for the first time.
+*/
+func UpdateClans(clans ...models.Clan) error {
+	return DefaultStorage.UpdateClans(clans...)
+}
+
+func (c *Client) UpdateClans(clans ...models.Clan) error {
+	if len(clans) == 0 {
+		return nil
+	}
+
+	var writes []mongo.WriteModel
+	for _, clan := range clans {
+		update := bson.M{
+			"tag":         clan.Tag,
+			"name":        clan.Name,
+			"realm":       clan.Realm,
+			"emblem":      clan.EmblemID,
+			"members":     clan.Members,
+			"lastUpdated": clan.LastUpdated,
+		}
+
+		model := mongo.NewUpdateOneModel()
+		model.SetFilter(bson.M{"_id": clan.ID})
+		model.SetUpdate(bson.M{"$set": update, "$setOnInsert": bson.M{"createdAt": clan.CreatedAt}})
+		model.SetUpsert(true)
+		writes = append(writes, model)
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionClans).BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func InsertClanMemberEvents(events ...models.ClanMemberEvent) error {
+	return DefaultStorage.InsertClanMemberEvents(events...)
+}
+
+func (c *Client) InsertClanMemberEvents(events ...models.ClanMemberEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var documents []interface{}
+	for _, event := range events {
+		documents = append(documents, event)
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionClanMemberEvents).InsertMany(ctx, documents)
+	return err
+}
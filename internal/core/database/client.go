@@ -29,6 +29,7 @@ func Connect(url string) error {
 		return err
 	}
 	DefaultClient = client
+	DefaultStorage = client
 	return nil
 }
 
@@ -14,11 +14,16 @@ const (
 	CollectionUserContent       = collectionName("user-content")
 	CollectionUserConnections   = collectionName("user-connections")
 	CollectionUserSubscriptions = collectionName("user-subscriptions")
+	CollectionUserRestrictions  = collectionName("user-restrictions")
 
 	CollectionClans                 = collectionName("clans")
+	CollectionClanMemberEvents      = collectionName("clan-member-events")
 	CollectionAccounts              = collectionName("accounts")
 	CollectionSessions              = collectionName("sessions")
+	CollectionSessionRollupsWeekly  = collectionName("session-rollups-weekly")
+	CollectionSessionRollupsMonthly = collectionName("session-rollups-monthly")
 	CollectionRatingSeasonSnapshots = collectionName("rating-season-snapshots")
+	CollectionAchievementsSnapshots = collectionName("achievements-snapshots")
 
 	CollectionVehicleAverages     = collectionName("vehicle-averages")
 	CollectionVehicleGlossary     = collectionName("glossary-vehicles")
@@ -27,6 +32,7 @@ const (
 	CollectionTasks         = collectionName("tasks")
 	CollectionMessages      = collectionName("messages")
 	CollectionConfiguration = collectionName("configuration")
+	CollectionAuditLog      = collectionName("audit-log")
 )
 
 /*
@@ -59,6 +65,10 @@ func init() {
 			Keys:    bson.M{"featureFlags": 1},
 			Options: options.Index().SetName("featureFlags"),
 		},
+		{
+			Keys:    bson.M{"sessionReset": 1},
+			Options: options.Index().SetSparse(true).SetName("sessionReset"),
+		},
 	})
 	addCollectionIndexes(CollectionUserContent, []Index{
 		{
@@ -100,8 +110,9 @@ func init() {
 			Keys: bson.D{
 				{Key: "userID", Value: 1},
 				{Key: "connectionType", Value: 1},
+				{Key: "connectionID", Value: 1},
 			},
-			Options: options.Index().SetUnique(true).SetName("userID-connectionType"),
+			Options: options.Index().SetUnique(true).SetName("userID-connectionType-connectionID"),
 		},
 		{
 			Keys: bson.D{
@@ -135,6 +146,15 @@ func init() {
 			Options: options.Index().SetName("referenceID-creationDate"),
 		},
 	})
+	addCollectionIndexes(CollectionUserRestrictions, []Index{
+		{
+			Keys: bson.D{
+				{Key: "userID", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("userID-createdAt"),
+		},
+	})
 
 	// Accounts, Clans, Sessions
 	addCollectionIndexes(CollectionAccounts, []Index{
@@ -156,11 +176,31 @@ func init() {
 			Keys:    bson.M{"name": 1},
 			Options: options.Index().SetName("name"),
 		},
+		{
+			Keys:    bson.M{"realm": 1},
+			Options: options.Index().SetName("realm"),
+		},
 		{
 			Keys:    bson.M{"members": 1},
 			Options: options.Index().SetName("members"),
 		},
 	})
+	addCollectionIndexes(CollectionClanMemberEvents, []Index{
+		{
+			Keys: bson.D{
+				{Key: "clanId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("clanId-createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "accountId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("accountId-createdAt"),
+		},
+	})
 	addCollectionIndexes(CollectionSessions, []Index{
 		{
 			Keys: bson.D{
@@ -190,30 +230,68 @@ func init() {
 			Options: options.Index().SetExpireAfterSeconds(172_800).SetName("createdAt"),
 		},
 	})
+	// Rollups expire based on the retention of each account, see models.RollupRetentionConfiguration
+	for _, collection := range []collectionName{CollectionSessionRollupsWeekly, CollectionSessionRollupsMonthly} {
+		addCollectionIndexes(collection, []Index{
+			{
+				Keys: bson.D{
+					{Key: "accountId", Value: 1},
+					{Key: "periodStart", Value: -1},
+				},
+				Options: options.Index().SetUnique(true).SetName("accountId-periodStart"),
+			},
+			{
+				Keys: bson.D{
+					{Key: "accountId", Value: 1},
+					{Key: "recordedAt", Value: -1},
+				},
+				Options: options.Index().SetName("accountId-recordedAt"),
+			},
+			{
+				Keys:    bson.M{"expiresAt": 1},
+				Options: options.Index().SetExpireAfterSeconds(0).SetName("expiresAt"),
+			},
+		})
+	}
+	addCollectionIndexes(CollectionAchievementsSnapshots, []Index{
+		{
+			Keys: bson.D{
+				{Key: "accountId", Value: 1},
+				{Key: "referenceId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("accountId-referenceId-createdAt"),
+		},
+		{
+			Keys:    bson.M{"createdAt": 1},
+			Options: options.Index().SetExpireAfterSeconds(2_592_000).SetName("createdAt"),
+		},
+	})
 	addCollectionIndexes(CollectionRatingSeasonSnapshots, []Index{
-		// {
-		// 	Keys: bson.D{
-		// 		{Key: "type", Value: 1},
-		// 		{Key: "accountId", Value: 1},
-		// 	},
-		// 	Options: options.Index().SetName("type-accountId"),
-		// },
-		// {
-		// 	Keys: bson.D{
-		// 		{Key: "type", Value: 1},
-		// 		{Key: "accountId", Value: 1},
-		// 		{Key: "createdAt", Value: -1},
-		// 	},
-		// 	Options: options.Index().SetName("type-accountId-createdAt"),
-		// },
-		// {
-		// 	Keys: bson.D{
-		// 		{Key: "type", Value: 1},
-		// 		{Key: "accountId", Value: 1},
-		// 		{Key: "lastBattleTime", Value: -1},
-		// 	},
-		// 	Options: options.Index().SetName("type-accountId-lastBattleTime"),
-		// },
+		{
+			Keys: bson.D{
+				{Key: "accountId", Value: 1},
+				{Key: "seasonId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("accountId-seasonId-createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "accountId", Value: 1},
+				{Key: "final", Value: 1},
+				{Key: "seasonId", Value: -1},
+			},
+			Options: options.Index().SetName("accountId-final-seasonId"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "realm", Value: 1},
+				{Key: "seasonId", Value: 1},
+				{Key: "final", Value: 1},
+			},
+			Options: options.Index().SetName("realm-seasonId-final"),
+		},
 	})
 
 	// Glossary
@@ -242,11 +320,47 @@ func init() {
 			},
 			Options: options.Index().SetName("status-scheduled_after"),
 		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "data.realm", Value: 1},
+				{Key: "priority", Value: -1},
+				{Key: "scheduled_after", Value: 1},
+			},
+			Options: options.Index().SetName("status-data.realm-priority-scheduled_after"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "lease_expires_at", Value: 1},
+			},
+			Options: options.Index().SetName("status-lease_expires_at"),
+		},
 		{
 			Keys:    bson.M{"createdAt": 1},
 			Options: options.Index().SetExpireAfterSeconds(604800).SetName("createdAt"),
 		},
 	})
+	addCollectionIndexes(CollectionAuditLog, []Index{
+		{
+			Keys:    bson.M{"createdAt": -1},
+			Options: options.Index().SetName("createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "actorId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("actorId-createdAt"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "targetUserId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().SetName("targetUserId-createdAt"),
+		},
+	})
 	addCollectionIndexes(CollectionMessages, []Index{
 		{
 			Keys:    bson.M{"userID": 1},
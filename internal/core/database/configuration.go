@@ -15,18 +15,26 @@ var (
 )
 
 func UpdateAppConfiguration[T any](key string, data T, metadata map[string]any, upsert bool) error {
-	var payload models.AppConfiguration[T]
+	value, err := marshalRawValue(data)
+	if err != nil {
+		return err
+	}
+	return DefaultStorage.UpdateAppConfiguration(key, value, metadata, upsert)
+}
+
+func (c *Client) UpdateAppConfiguration(key string, value bson.RawValue, metadata map[string]any, upsert bool) error {
+	var payload models.AppConfiguration[bson.RawValue]
 	payload.UpdatedAt = time.Now()
 	payload.Metadata = metadata
-	payload.Value = data
+	payload.Value = value
 	payload.Key = key
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	opts := options.Update().SetUpsert(upsert)
 
-	_, err := DefaultClient.Collection(CollectionConfiguration).UpdateOne(ctx, bson.M{"key": key}, bson.M{"$set": payload}, opts)
+	_, err := c.Collection(CollectionConfiguration).UpdateOne(ctx, bson.M{"_id": key}, bson.M{"$set": payload}, opts)
 	if err != nil {
 		return err
 	}
@@ -34,11 +42,25 @@ func UpdateAppConfiguration[T any](key string, data T, metadata map[string]any,
 }
 
 func GetAppConfiguration[T any](key string) (models.AppConfiguration[T], error) {
-	ctx, cancel := DefaultClient.Ctx()
+	raw, err := DefaultStorage.GetAppConfiguration(key)
+	if err != nil {
+		return models.AppConfiguration[T]{}, err
+	}
+
+	configuration := models.AppConfiguration[T]{
+		Key:       raw.Key,
+		Metadata:  raw.Metadata,
+		UpdatedAt: raw.UpdatedAt,
+	}
+	return configuration, unmarshalRawValue(raw.Value, &configuration.Value)
+}
+
+func (c *Client) GetAppConfiguration(key string) (models.AppConfiguration[bson.RawValue], error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	var content models.AppConfiguration[T]
-	err := DefaultClient.Collection(CollectionConfiguration).FindOne(ctx, bson.M{"key": key}).Decode(&content)
+	var content models.AppConfiguration[bson.RawValue]
+	err := c.Collection(CollectionConfiguration).FindOne(ctx, bson.M{"_id": key}).Decode(&content)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return content, ErrConfigurationNotFound
@@ -7,6 +7,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
@@ -14,11 +15,15 @@ var (
 )
 
 func FindUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.FindUserConnection(userId, connectionType)
+}
+
+func (c *Client) FindUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var connection models.UserConnection
-	err := DefaultClient.Collection(CollectionUserConnections).FindOne(ctx, bson.M{"userID": userId, "connectionType": connectionType}).Decode(&connection)
+	err := c.Collection(CollectionUserConnections).FindOne(ctx, bson.M{"userID": userId, "connectionType": connectionType}, defaultConnectionFirst()).Decode(&connection)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return connection, ErrConnectionNotFound
@@ -29,12 +34,26 @@ func FindUserConnection(userId string, connectionType models.ConnectionType) (mo
 	return connection, nil
 }
 
+/*
+FindUserConnectionOrDefault returns the user connection for externalID, or the default connection when externalID is blank
+*/
+func FindUserConnectionOrDefault(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	if externalID == "" {
+		return DefaultStorage.FindUserConnection(userId, connectionType)
+	}
+	return DefaultStorage.GetUserConnectionByExternalID(userId, connectionType, externalID)
+}
+
 func FindConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType) ([]models.UserConnection, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.FindConnectionsByReferenceID(referenceId, connectionType)
+}
+
+func (c *Client) FindConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType) ([]models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var connections []models.UserConnection
-	cur, err := DefaultClient.Collection(CollectionUserConnections).Find(ctx, bson.M{"connectionID": referenceId, "connectionType": connectionType})
+	cur, err := c.Collection(CollectionUserConnections).Find(ctx, bson.M{"connectionID": referenceId, "connectionType": connectionType})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrConnectionNotFound
@@ -44,12 +63,36 @@ func FindConnectionsByReferenceID(referenceId string, connectionType models.Conn
 	return connections, cur.All(ctx, &connections)
 }
 
+func FindConnectionsByReferenceIDs(connectionType models.ConnectionType, referenceIDs ...string) ([]models.UserConnection, error) {
+	return DefaultStorage.FindConnectionsByReferenceIDs(connectionType, referenceIDs...)
+}
+
+func (c *Client) FindConnectionsByReferenceIDs(connectionType models.ConnectionType, referenceIDs ...string) ([]models.UserConnection, error) {
+	if len(referenceIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var connections []models.UserConnection
+	cur, err := c.Collection(CollectionUserConnections).Find(ctx, bson.M{"connectionID": bson.M{"$in": referenceIDs}, "connectionType": connectionType})
+	if err != nil {
+		return nil, err
+	}
+	return connections, cur.All(ctx, &connections)
+}
+
 func GetUserConnections(userId string) ([]models.UserConnection, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetUserConnections(userId)
+}
+
+func (c *Client) GetUserConnections(userId string) ([]models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var connections []models.UserConnection
-	cur, err := DefaultClient.Collection(CollectionUserConnections).Find(ctx, bson.M{"userID": userId})
+	cur, err := c.Collection(CollectionUserConnections).Find(ctx, bson.M{"userID": userId})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrConnectionNotFound
@@ -61,11 +104,35 @@ func GetUserConnections(userId string) ([]models.UserConnection, error) {
 }
 
 func GetUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetUserConnection(userId, connectionType)
+}
+
+func (c *Client) GetUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var connection models.UserConnection
+	err := c.Collection(CollectionUserConnections).FindOne(ctx, bson.M{"userID": userId, "connectionType": connectionType}, defaultConnectionFirst()).Decode(&connection)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return connection, ErrConnectionNotFound
+		}
+		return connection, err
+	}
+
+	return connection, nil
+}
+
+func GetUserConnectionByExternalID(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	return DefaultStorage.GetUserConnectionByExternalID(userId, connectionType, externalID)
+}
+
+func (c *Client) GetUserConnectionByExternalID(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var connection models.UserConnection
-	err := DefaultClient.Collection(CollectionUserConnections).FindOne(ctx, bson.M{"userID": userId, "connectionType": connectionType}).Decode(&connection)
+	err := c.Collection(CollectionUserConnections).FindOne(ctx, bson.M{"userID": userId, "connectionType": connectionType, "connectionID": externalID}).Decode(&connection)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return connection, ErrConnectionNotFound
@@ -76,8 +143,16 @@ func GetUserConnection(userId string, connectionType models.ConnectionType) (mod
 	return connection, nil
 }
 
+/*
+AddUserConnection links a new connection to a user, the first connection of each type is marked as default.
+Linking another account never changes the default, it can only be changed with SetDefaultUserConnection.
+*/
 func AddUserConnection(userId string, connectionType models.ConnectionType, externalID string, metadata map[string]any) (models.UserConnection, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.AddUserConnection(userId, connectionType, externalID, metadata)
+}
+
+func (c *Client) AddUserConnection(userId string, connectionType models.ConnectionType, externalID string, metadata map[string]any) (models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	connection := models.UserConnection{
@@ -87,7 +162,14 @@ func AddUserConnection(userId string, connectionType models.ConnectionType, exte
 		Metadata:       metadata,
 	}
 
-	res, err := DefaultClient.Collection(CollectionUserConnections).InsertOne(ctx, connection)
+	// Connections saved before the default field was added have it missing, so any existing connection counts
+	existing, err := c.Collection(CollectionUserConnections).CountDocuments(ctx, bson.M{"userID": userId, "connectionType": connectionType})
+	if err != nil {
+		return models.UserConnection{}, err
+	}
+	connection.Default = existing == 0
+
+	res, err := c.Collection(CollectionUserConnections).InsertOne(ctx, connection)
 	if err != nil {
 		return models.UserConnection{}, err
 	}
@@ -100,11 +182,17 @@ func AddUserConnection(userId string, connectionType models.ConnectionType, exte
 	return connection, nil
 }
 
-func UpdateUserConnection(userId string, connectionType models.ConnectionType, payload models.ConnectionUpdate) (models.UserConnection, error) {
-	ctx, cancel := DefaultClient.Ctx()
+func UpdateUserConnection(userId string, connectionType models.ConnectionType, externalID string, payload models.ConnectionUpdate) (models.UserConnection, error) {
+	return DefaultStorage.UpdateUserConnection(userId, connectionType, externalID, payload)
+}
+
+func (c *Client) UpdateUserConnection(userId string, connectionType models.ConnectionType, externalID string, payload models.ConnectionUpdate) (models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionUserConnections).UpdateOne(ctx, bson.M{"userID": userId, "connectionType": connectionType}, bson.M{"$set": payload})
+	var connection models.UserConnection
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := c.Collection(CollectionUserConnections).FindOneAndUpdate(ctx, bson.M{"userID": userId, "connectionType": connectionType, "connectionID": externalID}, bson.M{"$set": payload}, opts).Decode(&connection)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return models.UserConnection{}, ErrConnectionNotFound
@@ -112,14 +200,113 @@ func UpdateUserConnection(userId string, connectionType models.ConnectionType, p
 		return models.UserConnection{}, err
 	}
 
-	return GetUserConnection(userId, connectionType)
+	return connection, nil
+}
+
+/*
+SetDefaultUserConnection makes a connection the default for its type, all other connections of this type are updated in the same write
+*/
+func SetDefaultUserConnection(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	return DefaultStorage.SetDefaultUserConnection(userId, connectionType, externalID)
+}
+
+func (c *Client) SetDefaultUserConnection(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	connection, err := c.GetUserConnectionByExternalID(userId, connectionType, externalID)
+	if err != nil {
+		return connection, err
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	// A pipeline update sets the flag on all connections of this type with one command instead of an unset followed by a set
+	update := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.M{"default": bson.M{"$eq": bson.A{"$_id", connection.ID}}}}}}
+	_, err = c.Collection(CollectionUserConnections).UpdateMany(ctx, bson.M{"userID": userId, "connectionType": connectionType}, update)
+	if err != nil {
+		return connection, err
+	}
+
+	connection.Default = true
+	return connection, nil
+}
+
+/*
+DeleteUserConnections removes connections of a given type from a user, all connections of this type are removed when no externalIDs are provided.
+When the default connection is removed, the oldest remaining connection becomes the new default.
+*/
+func DeleteUserConnections(userId string, connectionType models.ConnectionType, externalIDs ...string) ([]models.UserConnection, error) {
+	return DefaultStorage.DeleteUserConnections(userId, connectionType, externalIDs...)
+}
+
+func (c *Client) DeleteUserConnections(userId string, connectionType models.ConnectionType, externalIDs ...string) ([]models.UserConnection, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	filter := bson.M{"userID": userId, "connectionType": connectionType}
+	if len(externalIDs) > 0 {
+		filter["connectionID"] = bson.M{"$in": externalIDs}
+	}
+
+	var connections []models.UserConnection
+	cur, err := c.Collection(CollectionUserConnections).Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	err = cur.All(ctx, &connections)
+	if err != nil {
+		return nil, err
+	}
+	if len(connections) == 0 {
+		return nil, ErrConnectionNotFound
+	}
+
+	var ids []primitive.ObjectID
+	var removedDefault bool
+	for _, connection := range connections {
+		ids = append(ids, connection.ID)
+		removedDefault = removedDefault || connection.Default
+	}
+	_, err = c.Collection(CollectionUserConnections).DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	if !removedDefault {
+		return connections, nil
+	}
+
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"_id": 1})
+	err = c.Collection(CollectionUserConnections).FindOneAndUpdate(ctx, bson.M{"userID": userId, "connectionType": connectionType}, bson.M{"$set": bson.M{"default": true}}, opts).Err()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return connections, err
+	}
+	return connections, nil
+}
+
+/*
+UnlinkUserConnections removes user connections along with personal content referencing the unlinked accounts
+*/
+func UnlinkUserConnections(userId string, connectionType models.ConnectionType, externalIDs ...string) ([]models.UserConnection, error) {
+	removed, err := DefaultStorage.DeleteUserConnections(userId, connectionType, externalIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var referenceIDs []string
+	for _, connection := range removed {
+		referenceIDs = append(referenceIDs, connection.ExternalID)
+	}
+	return removed, DefaultStorage.DeleteUserContentByReferenceIDs(userId, referenceIDs, models.UserContentTypePersonalBackground)
 }
 
 func UpdateManyConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType, payload models.ConnectionUpdate) error {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.UpdateManyConnectionsByReferenceID(referenceId, connectionType, payload)
+}
+
+func (c *Client) UpdateManyConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType, payload models.ConnectionUpdate) error {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionUserConnections).UpdateMany(ctx, bson.M{"connectionID": referenceId, "connectionType": connectionType}, bson.M{"$set": payload})
+	_, err := c.Collection(CollectionUserConnections).UpdateMany(ctx, bson.M{"connectionID": referenceId, "connectionType": connectionType}, bson.M{"$set": payload})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return ErrConnectionNotFound
@@ -129,3 +316,7 @@ func UpdateManyConnectionsByReferenceID(referenceId string, connectionType model
 
 	return nil
 }
+
+func defaultConnectionFirst() *options.FindOneOptions {
+	return options.FindOne().SetSort(bson.D{{Key: "default", Value: -1}, {Key: "_id", Value: 1}})
+}
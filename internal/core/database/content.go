@@ -15,7 +15,15 @@ var (
 )
 
 func UpdateUserContent[T any](userID, referenceID string, contentType models.UserContentType, data T, metadata map[string]any, upsert bool) error {
-	var payload models.UserContent[T]
+	value, err := marshalRawValue(data)
+	if err != nil {
+		return err
+	}
+	return DefaultStorage.UpdateUserContent(userID, referenceID, contentType, value, metadata, upsert)
+}
+
+func (c *Client) UpdateUserContent(userID, referenceID string, contentType models.UserContentType, data bson.RawValue, metadata map[string]any, upsert bool) error {
+	var payload models.UserContent[bson.RawValue]
 	payload.ReferenceID = referenceID
 	payload.UpdatedAt = time.Now()
 	payload.Metadata = metadata
@@ -23,12 +31,12 @@ func UpdateUserContent[T any](userID, referenceID string, contentType models.Use
 	payload.Type = contentType
 	payload.Data = data
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	opts := options.Update().SetUpsert(upsert)
 
-	_, err := DefaultClient.Collection(CollectionUserContent).UpdateOne(ctx, bson.M{"userId": userID, "type": contentType}, bson.M{"$set": payload}, opts)
+	_, err := c.Collection(CollectionUserContent).UpdateOne(ctx, bson.M{"userID": userID, "type": contentType}, bson.M{"$set": payload}, opts)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return ErrUserContentNotFound
@@ -39,11 +47,19 @@ func UpdateUserContent[T any](userID, referenceID string, contentType models.Use
 }
 
 func UpdateUserContentReferenceID[T any](userID string, contentType models.UserContentType, newReferenceID string) (models.UserContent[T], error) {
-	ctx, cancel := DefaultClient.Ctx()
+	raw, err := DefaultStorage.UpdateUserContentReferenceID(userID, contentType, newReferenceID)
+	if err != nil {
+		return models.UserContent[T]{}, err
+	}
+	return decodeUserContent[T](raw)
+}
+
+func (c *Client) UpdateUserContentReferenceID(userID string, contentType models.UserContentType, newReferenceID string) (models.UserContent[bson.RawValue], error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	var content models.UserContent[T]
-	err := DefaultClient.Collection(CollectionUserContent).FindOneAndUpdate(ctx, bson.M{"userId": userID, "type": contentType}, bson.M{"$set": bson.M{"referenceId": newReferenceID}}).Decode(&content)
+	var content models.UserContent[bson.RawValue]
+	err := c.Collection(CollectionUserContent).FindOneAndUpdate(ctx, bson.M{"userID": userID, "type": contentType}, bson.M{"$set": bson.M{"referenceId": newReferenceID}}).Decode(&content)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return content, ErrUserContentNotFound
@@ -54,11 +70,19 @@ func UpdateUserContentReferenceID[T any](userID string, contentType models.UserC
 }
 
 func GetUserContent[T any](userID string, contentType ...models.UserContentType) (models.UserContent[T], error) {
-	ctx, cancel := DefaultClient.Ctx()
+	raw, err := DefaultStorage.GetUserContent(userID, contentType...)
+	if err != nil {
+		return models.UserContent[T]{}, err
+	}
+	return decodeUserContent[T](raw)
+}
+
+func (c *Client) GetUserContent(userID string, contentType ...models.UserContentType) (models.UserContent[bson.RawValue], error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	var content models.UserContent[T]
-	err := DefaultClient.Collection(CollectionUserContent).FindOne(ctx, bson.M{"userId": userID, "type": contentType}).Decode(&content)
+	var content models.UserContent[bson.RawValue]
+	err := c.Collection(CollectionUserContent).FindOne(ctx, bson.M{"userID": userID, "type": bson.M{"$in": contentType}}).Decode(&content)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return content, ErrUserContentNotFound
@@ -70,15 +94,32 @@ func GetUserContent[T any](userID string, contentType ...models.UserContentType)
 }
 
 func GetContentByReferenceIDs[T any](referenceIDs []string, contentType ...models.UserContentType) ([]models.UserContent[T], error) {
+	raw, err := DefaultStorage.GetContentByReferenceIDs(referenceIDs, contentType...)
+	if err != nil {
+		return nil, err
+	}
+
+	var content []models.UserContent[T]
+	for _, r := range raw {
+		decoded, err := decodeUserContent[T](r)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, decoded)
+	}
+	return content, nil
+}
+
+func (c *Client) GetContentByReferenceIDs(referenceIDs []string, contentType ...models.UserContentType) ([]models.UserContent[bson.RawValue], error) {
 	if len(referenceIDs) == 0 || len(contentType) == 0 {
 		return nil, ErrUserContentNotFound
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	var content []models.UserContent[T]
-	cur, err := DefaultClient.Collection(CollectionUserContent).Find(ctx, bson.M{"referenceId": bson.M{"$in": referenceIDs}, "type": bson.M{"$in": contentType}})
+	var content []models.UserContent[bson.RawValue]
+	cur, err := c.Collection(CollectionUserContent).Find(ctx, bson.M{"referenceId": bson.M{"$in": referenceIDs}, "type": bson.M{"$in": contentType}})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrUserContentNotFound
@@ -88,3 +129,34 @@ func GetContentByReferenceIDs[T any](referenceIDs []string, contentType ...model
 
 	return content, cur.All(ctx, &content)
 }
+
+/*
+DeleteUserContentByReferenceIDs removes content owned by a user which references any of referenceIDs, missing content is not an error
+*/
+func DeleteUserContentByReferenceIDs(userID string, referenceIDs []string, contentType ...models.UserContentType) error {
+	return DefaultStorage.DeleteUserContentByReferenceIDs(userID, referenceIDs, contentType...)
+}
+
+func (c *Client) DeleteUserContentByReferenceIDs(userID string, referenceIDs []string, contentType ...models.UserContentType) error {
+	if len(referenceIDs) == 0 || len(contentType) == 0 {
+		return nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionUserContent).DeleteMany(ctx, bson.M{"userID": userID, "referenceId": bson.M{"$in": referenceIDs}, "type": bson.M{"$in": contentType}})
+	return err
+}
+
+func decodeUserContent[T any](raw models.UserContent[bson.RawValue]) (models.UserContent[T], error) {
+	content := models.UserContent[T]{
+		ID:          raw.ID,
+		UserID:      raw.UserID,
+		ReferenceID: raw.ReferenceID,
+		Type:        raw.Type,
+		UpdatedAt:   raw.UpdatedAt,
+		Metadata:    raw.Metadata,
+	}
+	return content, unmarshalRawValue(raw.Data, &content.Data)
+}
@@ -8,6 +8,10 @@ import (
 )
 
 func UpdateAverages(averages map[int]stats.ReducedStatsFrame) error {
+	return DefaultStorage.UpdateAverages(averages)
+}
+
+func (c *Client) UpdateAverages(averages map[int]stats.ReducedStatsFrame) error {
 	var writes []mongo.WriteModel
 	for id, average := range averages {
 		data := models.TankAverages{
@@ -22,10 +26,10 @@ func UpdateAverages(averages map[int]stats.ReducedStatsFrame) error {
 		writes = append(writes, model)
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionVehicleAverages).BulkWrite(ctx, writes)
+	_, err := c.Collection(CollectionVehicleAverages).BulkWrite(ctx, writes)
 	if err != nil {
 		return err
 	}
@@ -34,15 +38,19 @@ func UpdateAverages(averages map[int]stats.ReducedStatsFrame) error {
 }
 
 func GetVehicleAverages(vehicleIDs ...int) (map[int]stats.ReducedStatsFrame, error) {
+	return DefaultStorage.GetVehicleAverages(vehicleIDs...)
+}
+
+func (c *Client) GetVehicleAverages(vehicleIDs ...int) (map[int]stats.ReducedStatsFrame, error) {
 	if len(vehicleIDs) == 0 {
 		return nil, nil
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var averages []models.TankAverages
-	cur, err := DefaultClient.Collection(CollectionVehicleAverages).Find(ctx, bson.M{"_id": bson.M{"$in": vehicleIDs}})
+	cur, err := c.Collection(CollectionVehicleAverages).Find(ctx, bson.M{"_id": bson.M{"$in": vehicleIDs}})
 	if err != nil {
 		return nil, err
 	}
@@ -61,15 +69,19 @@ func GetVehicleAverages(vehicleIDs ...int) (map[int]stats.ReducedStatsFrame, err
 }
 
 func GetGlossaryVehicles(vehicleIDs ...int) (map[int]models.Vehicle, error) {
+	return DefaultStorage.GetGlossaryVehicles(vehicleIDs...)
+}
+
+func (c *Client) GetGlossaryVehicles(vehicleIDs ...int) (map[int]models.Vehicle, error) {
 	if len(vehicleIDs) == 0 {
 		return nil, nil
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var vehicles []models.Vehicle
-	cur, err := DefaultClient.Collection(CollectionVehicleGlossary).Find(ctx, bson.M{"_id": bson.M{"$in": vehicleIDs}})
+	cur, err := c.Collection(CollectionVehicleGlossary).Find(ctx, bson.M{"_id": bson.M{"$in": vehicleIDs}})
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +100,10 @@ func GetGlossaryVehicles(vehicleIDs ...int) (map[int]models.Vehicle, error) {
 }
 
 func UpdateGlossary(vehicles []models.Vehicle) error {
+	return DefaultStorage.UpdateGlossary(vehicles)
+}
+
+func (c *Client) UpdateGlossary(vehicles []models.Vehicle) error {
 	var vehicleWrites []mongo.WriteModel
 	for _, vehicle := range vehicles {
 		model := mongo.NewUpdateOneModel()
@@ -97,10 +113,68 @@ func UpdateGlossary(vehicles []models.Vehicle) error {
 		vehicleWrites = append(vehicleWrites, model)
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionVehicleGlossary).BulkWrite(ctx, vehicleWrites)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func GetGlossaryAchievements(achievementIDs ...string) (map[string]models.Achievement, error) {
+	return DefaultStorage.GetGlossaryAchievements(achievementIDs...)
+}
+
+func (c *Client) GetGlossaryAchievements(achievementIDs ...string) (map[string]models.Achievement, error) {
+	if len(achievementIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var achievements []models.Achievement
+	cur, err := c.Collection(CollectionAchievementGlossary).Find(ctx, bson.M{"_id": bson.M{"$in": achievementIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = cur.All(ctx, &achievements)
+	if err != nil {
+		return nil, err
+	}
+
+	achievementMap := make(map[string]models.Achievement)
+	for _, achievement := range achievements {
+		achievementMap[achievement.ID] = achievement
+	}
+
+	return achievementMap, nil
+}
+
+func UpdateAchievementsGlossary(achievements []models.Achievement) error {
+	return DefaultStorage.UpdateAchievementsGlossary(achievements)
+}
+
+func (c *Client) UpdateAchievementsGlossary(achievements []models.Achievement) error {
+	var writes []mongo.WriteModel
+	for _, achievement := range achievements {
+		model := mongo.NewUpdateOneModel()
+		model.SetFilter(bson.M{"_id": achievement.ID})
+		model.SetUpdate(bson.M{"$set": achievement})
+		model.SetUpsert(true)
+		writes = append(writes, model)
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionVehicleGlossary).BulkWrite(ctx, vehicleWrites)
+	_, err := c.Collection(CollectionAchievementGlossary).BulkWrite(ctx, writes)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+)
+
+func (s *Storage) UpdatePlayerAccounts(accounts ...models.Account) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, account := range accounts {
+		// WN8 fields are omitted from the update when empty
+		if existing, ok := s.accounts[account.ID]; ok {
+			if account.WN8 == nil {
+				account.WN8 = existing.WN8
+			}
+			if account.WN8History == nil {
+				account.WN8History = existing.WN8History
+			}
+		}
+		s.accounts[account.ID] = account
+	}
+	return nil
+}
+
+func (s *Storage) UpdateAccountsWN8(values map[int]models.AccountWN8) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, value := range values {
+		account, ok := s.accounts[id]
+		if !ok {
+			continue
+		}
+
+		account.WN8 = &value
+		account.WN8History = append(append([]models.AccountWN8{}, account.WN8History...), value)
+		if len(account.WN8History) > models.AccountWN8HistoryLimit {
+			account.WN8History = account.WN8History[len(account.WN8History)-models.AccountWN8HistoryLimit:]
+		}
+		s.accounts[id] = account
+	}
+	return nil
+}
+
+func (s *Storage) GetPlayerAccount(id int) (models.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	account, ok := s.accounts[id]
+	if !ok {
+		return models.Account{}, database.ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func (s *Storage) GetRealmAccountIDs(realm string) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var accountIDs []int
+	for _, account := range s.accounts {
+		if account.Realm == realm {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+	return accountIDs, nil
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) AddAuditLogEntries(entries ...models.AuditLogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.ID = primitive.NewObjectID()
+		s.auditLog = append(s.auditLog, entry)
+	}
+	return nil
+}
+
+func (s *Storage) FindAuditLogEntries(query models.AuditLogQuery) ([]models.AuditLogEntry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var entries []models.AuditLogEntry
+	for _, entry := range s.auditLog {
+		if query.ActorID != "" && entry.ActorID != query.ActorID {
+			continue
+		}
+		if query.TargetUserID != "" && entry.TargetUserID != query.TargetUserID {
+			continue
+		}
+		if !query.From.IsZero() && entry.CreatedAt.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && entry.CreatedAt.After(query.To) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].CreatedAt.After(entries[j].CreatedAt) })
+
+	if query.Limit > 0 && len(entries) > query.Limit {
+		entries = entries[:query.Limit]
+	}
+	return entries, nil
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) GetClans(clanIDs ...int) (map[int]models.Clan, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clans := make(map[int]models.Clan)
+	for _, id := range clanIDs {
+		if clan, ok := s.clans[id]; ok {
+			clans[id] = clan
+		}
+	}
+	return clans, nil
+}
+
+func (s *Storage) GetRealmClanIDs(realm string) ([]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var clanIDs []int
+	for _, clan := range s.clans {
+		if clan.Realm == realm || clan.Realm == "" {
+			clanIDs = append(clanIDs, clan.ID)
+		}
+	}
+	slices.Sort(clanIDs)
+	return clanIDs, nil
+}
+
+func (s *Storage) UpdateClans(clans ...models.Clan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, clan := range clans {
+		// createdAt is only set on insert
+		if existing, ok := s.clans[clan.ID]; ok {
+			clan.CreatedAt = existing.CreatedAt
+		}
+		s.clans[clan.ID] = clan
+	}
+	return nil
+}
+
+func (s *Storage) InsertClanMemberEvents(events ...models.ClanMemberEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, event := range events {
+		event.ID = primitive.NewObjectID()
+		s.clanMemberEvents = append(s.clanMemberEvents, event)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (s *Storage) UpdateAppConfiguration(key string, value bson.RawValue, metadata map[string]any, upsert bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.configuration[key]; !ok && !upsert {
+		return nil
+	}
+
+	s.configuration[key] = models.AppConfiguration[bson.RawValue]{
+		Key:       key,
+		Value:     value,
+		Metadata:  metadata,
+		UpdatedAt: time.Now(),
+	}
+	return nil
+}
+
+func (s *Storage) GetAppConfiguration(key string) (models.AppConfiguration[bson.RawValue], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	configuration, ok := s.configuration[key]
+	if !ok {
+		return configuration, database.ErrConfigurationNotFound
+	}
+	return configuration, nil
+}
@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"slices"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) FindUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error) {
+	return s.GetUserConnection(userId, connectionType)
+}
+
+func (s *Storage) FindConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType) ([]models.UserConnection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var connections []models.UserConnection
+	for _, connection := range s.connections {
+		if connection.ExternalID == referenceId && connection.ConnectionType == connectionType {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (s *Storage) FindConnectionsByReferenceIDs(connectionType models.ConnectionType, referenceIDs ...string) ([]models.UserConnection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var connections []models.UserConnection
+	for _, connection := range s.connections {
+		if slices.Contains(referenceIDs, connection.ExternalID) && connection.ConnectionType == connectionType {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (s *Storage) GetUserConnections(userId string) ([]models.UserConnection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var connections []models.UserConnection
+	for _, connection := range s.connections {
+		if connection.UserID == userId {
+			connections = append(connections, connection)
+		}
+	}
+	return connections, nil
+}
+
+func (s *Storage) GetUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var found *models.UserConnection
+	for _, connection := range s.connections {
+		if connection.UserID == userId && connection.ConnectionType == connectionType {
+			if connection.Default {
+				return connection, nil
+			}
+			if found == nil {
+				found = &connection
+			}
+		}
+	}
+	if found == nil {
+		return models.UserConnection{}, database.ErrConnectionNotFound
+	}
+	return *found, nil
+}
+
+func (s *Storage) GetUserConnectionByExternalID(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, connection := range s.connections {
+		if connection.UserID == userId && connection.ConnectionType == connectionType && connection.ExternalID == externalID {
+			return connection, nil
+		}
+	}
+	return models.UserConnection{}, database.ErrConnectionNotFound
+}
+
+func (s *Storage) AddUserConnection(userId string, connectionType models.ConnectionType, externalID string, metadata map[string]any) (models.UserConnection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	connection := models.UserConnection{
+		ID:             primitive.NewObjectID(),
+		UserID:         userId,
+		ConnectionType: connectionType,
+		ExternalID:     externalID,
+		Default:        true,
+		Metadata:       metadata,
+	}
+	for _, existing := range s.connections {
+		if existing.UserID == userId && existing.ConnectionType == connectionType {
+			connection.Default = false
+			break
+		}
+	}
+	s.connections = append(s.connections, connection)
+	return connection, nil
+}
+
+func (s *Storage) UpdateUserConnection(userId string, connectionType models.ConnectionType, externalID string, payload models.ConnectionUpdate) (models.UserConnection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, connection := range s.connections {
+		if connection.UserID == userId && connection.ConnectionType == connectionType && connection.ExternalID == externalID {
+			s.connections[i] = applyConnectionUpdate(connection, payload)
+			return s.connections[i], nil
+		}
+	}
+	return models.UserConnection{}, database.ErrConnectionNotFound
+}
+
+func (s *Storage) SetDefaultUserConnection(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := -1
+	for i, connection := range s.connections {
+		if connection.UserID == userId && connection.ConnectionType == connectionType && connection.ExternalID == externalID {
+			index = i
+		}
+	}
+	if index < 0 {
+		return models.UserConnection{}, database.ErrConnectionNotFound
+	}
+
+	for i, connection := range s.connections {
+		if connection.UserID == userId && connection.ConnectionType == connectionType {
+			s.connections[i].Default = i == index
+		}
+	}
+	return s.connections[index], nil
+}
+
+func (s *Storage) DeleteUserConnections(userId string, connectionType models.ConnectionType, externalIDs ...string) ([]models.UserConnection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []models.UserConnection
+	var removedDefault bool
+	s.connections = slices.DeleteFunc(s.connections, func(connection models.UserConnection) bool {
+		if connection.UserID != userId || connection.ConnectionType != connectionType {
+			return false
+		}
+		if len(externalIDs) > 0 && !slices.Contains(externalIDs, connection.ExternalID) {
+			return false
+		}
+		removed = append(removed, connection)
+		removedDefault = removedDefault || connection.Default
+		return true
+	})
+	if len(removed) == 0 {
+		return nil, database.ErrConnectionNotFound
+	}
+
+	if removedDefault {
+		for i, connection := range s.connections {
+			if connection.UserID == userId && connection.ConnectionType == connectionType {
+				s.connections[i].Default = true
+				break
+			}
+		}
+	}
+	return removed, nil
+}
+
+func (s *Storage) UpdateManyConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType, payload models.ConnectionUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, connection := range s.connections {
+		if connection.ExternalID == referenceId && connection.ConnectionType == connectionType {
+			s.connections[i] = applyConnectionUpdate(connection, payload)
+		}
+	}
+	return nil
+}
+
+func applyConnectionUpdate(connection models.UserConnection, payload models.ConnectionUpdate) models.UserConnection {
+	if payload.ExternalID != nil {
+		connection.ExternalID = *payload.ExternalID
+	}
+	if payload.Permissions != nil {
+		connection.Permissions = *payload.Permissions
+	}
+	if payload.Metadata != nil {
+		connection.Metadata = payload.Metadata
+	}
+	return connection
+}
@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) UpdateUserContent(userID, referenceID string, contentType models.UserContentType, data bson.RawValue, metadata map[string]any, upsert bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload := models.UserContent[bson.RawValue]{
+		UserID:      userID,
+		ReferenceID: referenceID,
+		Type:        contentType,
+		UpdatedAt:   time.Now(),
+		Data:        data,
+		Metadata:    metadata,
+	}
+
+	for i, content := range s.content {
+		if content.UserID == userID && content.Type == contentType {
+			payload.ID = content.ID
+			s.content[i] = payload
+			return nil
+		}
+	}
+	if !upsert {
+		return nil
+	}
+
+	payload.ID = primitive.NewObjectID()
+	s.content = append(s.content, payload)
+	return nil
+}
+
+func (s *Storage) UpdateUserContentReferenceID(userID string, contentType models.UserContentType, newReferenceID string) (models.UserContent[bson.RawValue], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, content := range s.content {
+		if content.UserID == userID && content.Type == contentType {
+			s.content[i].ReferenceID = newReferenceID
+			return content, nil
+		}
+	}
+	return models.UserContent[bson.RawValue]{}, database.ErrUserContentNotFound
+}
+
+func (s *Storage) GetUserContent(userID string, contentType ...models.UserContentType) (models.UserContent[bson.RawValue], error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, content := range s.content {
+		if content.UserID == userID && slices.Contains(contentType, content.Type) {
+			return content, nil
+		}
+	}
+	return models.UserContent[bson.RawValue]{}, database.ErrUserContentNotFound
+}
+
+func (s *Storage) GetContentByReferenceIDs(referenceIDs []string, contentType ...models.UserContentType) ([]models.UserContent[bson.RawValue], error) {
+	if len(referenceIDs) == 0 || len(contentType) == 0 {
+		return nil, database.ErrUserContentNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var content []models.UserContent[bson.RawValue]
+	for _, c := range s.content {
+		if slices.Contains(referenceIDs, c.ReferenceID) && slices.Contains(contentType, c.Type) {
+			content = append(content, c)
+		}
+	}
+	return content, nil
+}
+
+func (s *Storage) DeleteUserContentByReferenceIDs(userID string, referenceIDs []string, contentType ...models.UserContentType) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.content = slices.DeleteFunc(s.content, func(c models.UserContent[bson.RawValue]) bool {
+		return c.UserID == userID && slices.Contains(referenceIDs, c.ReferenceID) && slices.Contains(contentType, c.Type)
+	})
+	return nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/stats"
+)
+
+func (s *Storage) UpdateAverages(averages map[int]stats.ReducedStatsFrame) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, average := range averages {
+		s.averages[id] = average
+	}
+	return nil
+}
+
+func (s *Storage) GetVehicleAverages(vehicleIDs ...int) (map[int]stats.ReducedStatsFrame, error) {
+	if len(vehicleIDs) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	averages := make(map[int]stats.ReducedStatsFrame)
+	for _, id := range vehicleIDs {
+		if average, ok := s.averages[id]; ok {
+			averages[id] = average
+		}
+	}
+	return averages, nil
+}
+
+func (s *Storage) GetGlossaryVehicles(vehicleIDs ...int) (map[int]models.Vehicle, error) {
+	if len(vehicleIDs) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vehicles := make(map[int]models.Vehicle)
+	for _, id := range vehicleIDs {
+		if vehicle, ok := s.vehicleGlossary[id]; ok {
+			vehicles[id] = vehicle
+		}
+	}
+	return vehicles, nil
+}
+
+func (s *Storage) UpdateGlossary(vehicles []models.Vehicle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, vehicle := range vehicles {
+		s.vehicleGlossary[vehicle.ID] = vehicle
+	}
+	return nil
+}
+
+func (s *Storage) GetGlossaryAchievements(achievementIDs ...string) (map[string]models.Achievement, error) {
+	if len(achievementIDs) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	achievements := make(map[string]models.Achievement)
+	for _, id := range achievementIDs {
+		if achievement, ok := s.achievementsGlossary[id]; ok {
+			achievements[id] = achievement
+		}
+	}
+	return achievements, nil
+}
+
+func (s *Storage) UpdateAchievementsGlossary(achievements []models.Achievement) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, achievement := range achievements {
+		s.achievementsGlossary[achievement.ID] = achievement
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) NewNonce(referenceID string, duration time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nonce := models.Nonce{
+		ID:          primitive.NewObjectID(),
+		ReferenceID: referenceID,
+		ExpiresAt:   time.Now().Add(duration),
+		CreatedAt:   time.Now(),
+	}
+	s.nonces[nonce.ID] = nonce
+	return nonce.ID.Hex(), nil
+}
+
+func (s *Storage) GetNonceByID(id string) (models.Nonce, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.Nonce{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nonce, ok := s.nonces[oid]
+	if !ok || !nonce.ExpiresAt.After(time.Now()) {
+		return models.Nonce{}, database.ErrNonceNotFound
+	}
+	return nonce, nil
+}
+
+func (s *Storage) ExpireNonceByID(id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if nonce, ok := s.nonces[oid]; ok {
+		nonce.ExpiresAt = time.Now()
+		s.nonces[oid] = nonce
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) GetUserRestriction(id primitive.ObjectID) (models.UserRestriction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, restriction := range s.restrictions {
+		if restriction.ID == id {
+			return restriction, nil
+		}
+	}
+	return models.UserRestriction{}, database.ErrRestrictionNotFound
+}
+
+func (s *Storage) FindUserRestrictions(userId string) ([]models.UserRestriction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var restrictions []models.UserRestriction
+	for _, restriction := range s.restrictions {
+		if restriction.UserID == userId {
+			restrictions = append(restrictions, restriction)
+		}
+	}
+	sort.SliceStable(restrictions, func(i, j int) bool {
+		return restrictions[i].CreatedAt.After(restrictions[j].CreatedAt)
+	})
+	return restrictions, nil
+}
+
+func (s *Storage) AddUserRestriction(payload models.UserRestriction) (models.UserRestriction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload.ID = primitive.NewObjectID()
+	s.restrictions = append(s.restrictions, payload)
+	return payload, nil
+}
+
+func (s *Storage) UpdateUserRestriction(id primitive.ObjectID, payload models.RestrictionUpdate) (models.UserRestriction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, restriction := range s.restrictions {
+		if restriction.ID != id {
+			continue
+		}
+		if payload.Scopes != nil {
+			restriction.Scopes = *payload.Scopes
+		}
+		if payload.UserMessage != nil {
+			restriction.UserMessage = *payload.UserMessage
+		}
+		if payload.Comment != nil {
+			restriction.Comment = *payload.Comment
+		}
+		if payload.ExpiresAt != nil {
+			restriction.ExpiresAt = *payload.ExpiresAt
+		}
+		s.restrictions[i] = restriction
+		return restriction, nil
+	}
+	return models.UserRestriction{}, database.ErrRestrictionNotFound
+}
+
+func (s *Storage) DeleteUserRestriction(id primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, restriction := range s.restrictions {
+		if restriction.ID == id {
+			s.restrictions = append(s.restrictions[:i], s.restrictions[i+1:]...)
+			return nil
+		}
+	}
+	return database.ErrRestrictionNotFound
+}
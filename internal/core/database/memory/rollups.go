@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) GetLatestSessionSnapshots(sessionType models.SessionType, accountIDs ...int) (map[int]models.Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := make(map[int]models.Snapshot)
+	for _, snapshot := range s.sessions {
+		if snapshot.Type != sessionType || snapshot.ReferenceID != "" || !slices.Contains(accountIDs, snapshot.Session.AccountID) {
+			continue
+		}
+		if current, ok := snapshots[snapshot.Session.AccountID]; !ok || !snapshot.CreatedAt.Before(current.CreatedAt) {
+			snapshots[snapshot.Session.AccountID] = snapshot
+		}
+	}
+	return snapshots, nil
+}
+
+func (s *Storage) UpsertSessionRollups(rollups ...models.SessionRollup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rollup := range rollups {
+		index := slices.IndexFunc(s.rollups, func(r models.SessionRollup) bool {
+			return r.Period == rollup.Period && r.Session.AccountID == rollup.Session.AccountID && r.PeriodStart.Equal(rollup.PeriodStart)
+		})
+		if index >= 0 {
+			if rollup.ExpiresAt.After(s.rollups[index].ExpiresAt) {
+				s.rollups[index].ExpiresAt = rollup.ExpiresAt
+			}
+			continue
+		}
+
+		rollup.ID = primitive.NewObjectID()
+		rollup.CreatedAt = time.Now()
+		s.rollups = append(s.rollups, rollup)
+	}
+	return nil
+}
+
+func (s *Storage) GetSessionRollup(period models.RollupPeriod, accountID int, before time.Time) (models.SessionRollup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *models.SessionRollup
+	for i, rollup := range s.rollups {
+		if rollup.Period != period || rollup.Session.AccountID != accountID || rollup.RecordedAt.After(before) {
+			continue
+		}
+		if rollup.ExpiresAt.Before(time.Now()) {
+			continue
+		}
+		if latest == nil || rollup.RecordedAt.After(latest.RecordedAt) {
+			latest = &s.rollups[i]
+		}
+	}
+
+	if latest == nil {
+		return models.SessionRollup{}, database.ErrNoSessionRollup
+	}
+	return *latest, nil
+}
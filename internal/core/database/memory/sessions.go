@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/stats"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) GetPlayerSessionSnapshot(accountID int, o ...database.SessionGetOptions) (models.Snapshot, error) {
+	opts := database.SessionGetOptions{Type: models.SessionTypeDaily}
+	if len(o) > 0 {
+		opts = o[0]
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *models.Snapshot
+	for i, snapshot := range s.sessions {
+		if snapshot.Session.AccountID != accountID {
+			continue
+		}
+		if opts.Type != "" && snapshot.Type != opts.Type {
+			continue
+		}
+		if opts.ReferenceID != nil && snapshot.ReferenceID != *opts.ReferenceID {
+			continue
+		}
+		if opts.LastBattleBefore != nil && snapshot.Session.LastBattleTime >= *opts.LastBattleBefore {
+			continue
+		}
+		if opts.LastBattleAfter != nil && snapshot.Session.LastBattleTime <= *opts.LastBattleAfter {
+			continue
+		}
+		if opts.CreatedBefore != nil && snapshot.CreatedAt.After(*opts.CreatedBefore) {
+			continue
+		}
+		if latest == nil || !snapshot.CreatedAt.Before(latest.CreatedAt) {
+			latest = &s.sessions[i]
+		}
+	}
+
+	if latest == nil {
+		return models.Snapshot{}, database.ErrNoSessionCache
+	}
+	return *latest, nil
+}
+
+func (s *Storage) GetLastBattleTimes(sessionType models.SessionType, referenceId *string, accountIDs ...int) (map[int]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lastBattles := make(map[int]int)
+	for _, snapshot := range s.sessions {
+		if snapshot.Type != sessionType || !slices.Contains(accountIDs, snapshot.Session.AccountID) {
+			continue
+		}
+		if referenceId != nil && snapshot.ReferenceID != *referenceId {
+			continue
+		}
+		if current, ok := lastBattles[snapshot.Session.AccountID]; !ok || snapshot.Session.LastBattleTime > current {
+			lastBattles[snapshot.Session.AccountID] = snapshot.Session.LastBattleTime
+		}
+	}
+	return lastBattles, nil
+}
+
+func (s *Storage) InsertSession(sessionType models.SessionType, referenceId *string, sessions ...stats.SessionSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, session := range sessions {
+		snapshot := models.Snapshot{
+			ID:        primitive.NewObjectID(),
+			Type:      sessionType,
+			CreatedAt: time.Now(),
+			Session:   session,
+		}
+		if referenceId != nil {
+			snapshot.ReferenceID = *referenceId
+		}
+		s.sessions = append(s.sessions, snapshot)
+	}
+	return nil
+}
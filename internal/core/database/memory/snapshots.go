@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) InsertAchievementsSnapshots(snapshots ...models.AchievementsSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		snapshot.ID = primitive.NewObjectID()
+		s.achievementsSnapshots = append(s.achievementsSnapshots, snapshot)
+	}
+	return nil
+}
+
+func (s *Storage) GetLastAchievementsSnapshot(accountID int, referenceID string) (models.AchievementsSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *models.AchievementsSnapshot
+	for i, snapshot := range s.achievementsSnapshots {
+		if snapshot.AccountID != accountID || snapshot.ReferenceID != referenceID {
+			continue
+		}
+		if latest == nil || !snapshot.CreatedAt.Before(latest.CreatedAt) {
+			latest = &s.achievementsSnapshots[i]
+		}
+	}
+
+	if latest == nil {
+		return models.AchievementsSnapshot{}, database.ErrNoAchievementsSnapshot
+	}
+	return *latest, nil
+}
+
+func (s *Storage) InsertRatingSnapshots(snapshots ...models.RatingSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		snapshot.ID = primitive.NewObjectID()
+		s.ratingSnapshots = append(s.ratingSnapshots, snapshot)
+	}
+	return nil
+}
+
+func (s *Storage) GetRatingSnapshots(accountID, seasonID int) ([]models.RatingSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var snapshots []models.RatingSnapshot
+	for _, snapshot := range s.ratingSnapshots {
+		if snapshot.AccountID == accountID && snapshot.SeasonID == seasonID {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+func (s *Storage) GetFinalRatingSnapshots(accountID int) ([]models.RatingSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var snapshots []models.RatingSnapshot
+	for _, snapshot := range s.ratingSnapshots {
+		if snapshot.AccountID == accountID && snapshot.Final {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].SeasonID > snapshots[j].SeasonID })
+	return snapshots, nil
+}
+
+func (s *Storage) GetLastRatingSnapshots(seasonID int, accountIDs ...int) (map[int]models.RatingSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshots := make(map[int]models.RatingSnapshot)
+	for _, snapshot := range s.ratingSnapshots {
+		if snapshot.SeasonID != seasonID || !slices.Contains(accountIDs, snapshot.AccountID) {
+			continue
+		}
+		if current, ok := snapshots[snapshot.AccountID]; !ok || !snapshot.CreatedAt.Before(current.CreatedAt) {
+			snapshots[snapshot.AccountID] = snapshot
+		}
+	}
+	return snapshots, nil
+}
+
+func (s *Storage) CloseRatingSeasons(realm string, currentSeasonID int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type seasonKey struct{ accountID, seasonID int }
+	closed := make(map[seasonKey]bool)
+	last := make(map[seasonKey]int)
+	for i, snapshot := range s.ratingSnapshots {
+		if snapshot.Realm != realm || snapshot.SeasonID >= currentSeasonID {
+			continue
+		}
+		key := seasonKey{snapshot.AccountID, snapshot.SeasonID}
+		closed[key] = closed[key] || snapshot.Final
+		if current, ok := last[key]; !ok || !snapshot.CreatedAt.Before(s.ratingSnapshots[current].CreatedAt) {
+			last[key] = i
+		}
+	}
+
+	var updated int
+	for key, index := range last {
+		if closed[key] {
+			continue
+		}
+		s.ratingSnapshots[index].Final = true
+		updated++
+	}
+	return updated, nil
+}
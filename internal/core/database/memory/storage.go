@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/stats"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ database.Storage = &Storage{}
+
+/*
+Storage is an in-memory implementation of database.Storage meant for tests, it honors the same filters as the MongoDB client.
+*/
+type Storage struct {
+	mu sync.RWMutex
+
+	accounts         map[int]models.Account
+	clans            map[int]models.Clan
+	clanMemberEvents []models.ClanMemberEvent
+	sessions         []models.Snapshot
+	rollups          []models.SessionRollup
+
+	achievementsSnapshots []models.AchievementsSnapshot
+	ratingSnapshots       []models.RatingSnapshot
+
+	users         map[string]models.User
+	connections   []models.UserConnection
+	subscriptions []models.UserSubscription
+	restrictions  []models.UserRestriction
+	content       []models.UserContent[bson.RawValue]
+
+	averages             map[int]stats.ReducedStatsFrame
+	vehicleGlossary      map[int]models.Vehicle
+	achievementsGlossary map[string]models.Achievement
+
+	nonces        map[primitive.ObjectID]models.Nonce
+	tasks         []models.Task
+	configuration map[string]models.AppConfiguration[bson.RawValue]
+	auditLog      []models.AuditLogEntry
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		accounts:             make(map[int]models.Account),
+		clans:                make(map[int]models.Clan),
+		users:                make(map[string]models.User),
+		averages:             make(map[int]stats.ReducedStatsFrame),
+		vehicleGlossary:      make(map[int]models.Vehicle),
+		achievementsGlossary: make(map[string]models.Achievement),
+		nonces:               make(map[primitive.ObjectID]models.Nonce),
+		configuration:        make(map[string]models.AppConfiguration[bson.RawValue]),
+	}
+}
@@ -0,0 +1,462 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/stats"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSessionOptions(t *testing.T) {
+	storage := NewStorage()
+	reference := "reference"
+
+	err := storage.InsertSession(models.SessionTypeDaily, nil, stats.SessionSnapshot{AccountID: 1, LastBattleTime: 100}, stats.SessionSnapshot{AccountID: 1, LastBattleTime: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storage.InsertSession(models.SessionTypeDaily, &reference, stats.SessionSnapshot{AccountID: 1, LastBattleTime: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := 200
+	snapshot, err := storage.GetPlayerSessionSnapshot(1, database.SessionGetOptions{Type: models.SessionTypeDaily, LastBattleBefore: &before})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Session.LastBattleTime != 100 {
+		t.Errorf("expected last battle time 100, got %d", snapshot.Session.LastBattleTime)
+	}
+
+	snapshot, err = storage.GetPlayerSessionSnapshot(1, database.SessionGetOptions{Type: models.SessionTypeDaily, ReferenceID: &reference})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Session.LastBattleTime != 300 {
+		t.Errorf("expected last battle time 300, got %d", snapshot.Session.LastBattleTime)
+	}
+
+	_, err = storage.GetPlayerSessionSnapshot(2)
+	if err != database.ErrNoSessionCache {
+		t.Errorf("expected ErrNoSessionCache, got %v", err)
+	}
+}
+
+func TestCompleteUser(t *testing.T) {
+	storage := NewStorage()
+
+	_, err := storage.CreateUser("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storage.AddUserConnection("user", models.ConnectionTypeWargaming, "1013072123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := storage.FindUserByConnection(models.ConnectionTypeWargaming, "1013072123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "user" || len(user.Connections) != 1 {
+		t.Errorf("unexpected user: %+v", user)
+	}
+
+	_, err = storage.GetUserByID("missing")
+	if err != database.ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestDefaultUserConnection(t *testing.T) {
+	storage := NewStorage()
+
+	_, err := storage.AddUserConnection("user", models.ConnectionTypeWargaming, "1013072123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := storage.AddUserConnection("user", models.ConnectionTypeWargaming, "579178315", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.Default {
+		t.Errorf("expected only the first connection to be default")
+	}
+
+	connection, err := storage.FindUserConnection("user", models.ConnectionTypeWargaming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if connection.ExternalID != "1013072123" {
+		t.Errorf("expected default connection 1013072123, got %s", connection.ExternalID)
+	}
+
+	_, err = storage.SetDefaultUserConnection("user", models.ConnectionTypeWargaming, "579178315")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connection, err = storage.FindUserConnection("user", models.ConnectionTypeWargaming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if connection.ExternalID != "579178315" {
+		t.Errorf("expected default connection 579178315, got %s", connection.ExternalID)
+	}
+
+	_, err = storage.SetDefaultUserConnection("user", models.ConnectionTypeWargaming, "1")
+	if err != database.ErrConnectionNotFound {
+		t.Errorf("expected ErrConnectionNotFound, got %v", err)
+	}
+}
+
+func TestUnlinkUserConnections(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	storage := NewStorage()
+	database.DefaultStorage = storage
+
+	for _, account := range []string{"1013072123", "579178315"} {
+		_, err := storage.AddUserConnection("user", models.ConnectionTypeWargaming, account, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := database.UpdateUserContent("user", "1013072123", models.UserContentTypePersonalBackground, "https://example.com/image.png", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := database.UnlinkUserConnections("user", models.ConnectionTypeWargaming, "1013072123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 {
+		t.Errorf("expected 1 removed connection, got %d", len(removed))
+	}
+
+	connection, err := storage.FindUserConnection("user", models.ConnectionTypeWargaming)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if connection.ExternalID != "579178315" || !connection.Default {
+		t.Errorf("expected 579178315 to become the default connection, got %+v", connection)
+	}
+
+	_, err = storage.GetUserContent("user", models.UserContentTypePersonalBackground)
+	if err != database.ErrUserContentNotFound {
+		t.Errorf("expected ErrUserContentNotFound, got %v", err)
+	}
+
+	_, err = database.UnlinkUserConnections("user", models.ConnectionTypeWargaming, "1013072123")
+	if err != database.ErrConnectionNotFound {
+		t.Errorf("expected ErrConnectionNotFound, got %v", err)
+	}
+}
+
+func TestUserSessionReset(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	storage := NewStorage()
+	database.DefaultStorage = storage
+
+	for _, id := range []string{"user", "other"} {
+		_, err := storage.CreateUser(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := database.UpdateUserSessionReset("user", &models.SessionReset{Hour: 4, Timezone: "Europe/Berlin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = database.UpdateUserSessionReset("missing", &models.SessionReset{Hour: 4, Timezone: "UTC"})
+	if err != database.ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+
+	users, err := database.FindUsersWithSessionReset()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].ID != "user" || users[0].SessionReset.Hour != 4 {
+		t.Fatalf("unexpected users %+v", users)
+	}
+
+	_, err = database.UpdateUserSessionReset("user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, _ = database.FindUsersWithSessionReset()
+	if len(users) != 0 {
+		t.Errorf("expected no users with a session reset, got %d", len(users))
+	}
+}
+
+func TestSessionRollups(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	storage := NewStorage()
+	database.DefaultStorage = storage
+
+	err := storage.InsertSession(models.SessionTypeDaily, nil, stats.SessionSnapshot{AccountID: 1, LastBattleTime: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reference := "reference"
+	err = storage.InsertSession(models.SessionTypeDaily, &reference, stats.SessionSnapshot{AccountID: 1, LastBattleTime: 200})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := database.GetLatestSessionSnapshots(models.SessionTypeDaily, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 1 || latest[1].Session.LastBattleTime != 100 {
+		t.Fatalf("expected only the realm-wide snapshot, got %+v", latest)
+	}
+
+	start := models.RollupPeriodWeekly.Start(time.Now())
+	rollup := models.SessionRollup{Period: models.RollupPeriodWeekly, PeriodStart: start, RecordedAt: start.Add(time.Hour), ExpiresAt: start.Add(time.Hour * 24 * 7), Session: latest[1].Session}
+	err = database.UpsertSessionRollups(rollup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first snapshot in a period is kept, only the expiration can be extended
+	rollup.Session.LastBattleTime = 300
+	rollup.ExpiresAt = start.Add(time.Hour * 24 * 30)
+	err = database.UpsertSessionRollups(rollup)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := database.GetSessionRollup(models.RollupPeriodWeekly, 1, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Session.LastBattleTime != 100 || !found.ExpiresAt.Equal(rollup.ExpiresAt) {
+		t.Errorf("unexpected rollup %+v", found)
+	}
+
+	// Rollups are selected by the time their snapshot was recorded, not by the period start
+	_, err = database.GetSessionRollup(models.RollupPeriodWeekly, 1, start.Add(time.Minute))
+	if err != database.ErrNoSessionRollup {
+		t.Errorf("expected ErrNoSessionRollup, got %v", err)
+	}
+	_, err = database.GetSessionRollup(models.RollupPeriodMonthly, 1, time.Now())
+	if err != database.ErrNoSessionRollup {
+		t.Errorf("expected ErrNoSessionRollup, got %v", err)
+	}
+}
+
+func TestRealmClanIDs(t *testing.T) {
+	storage := NewStorage()
+
+	err := storage.UpdateClans(models.Clan{ID: 1, Realm: "NA"}, models.Clan{ID: 2, Realm: "EU"}, models.Clan{ID: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clans without a realm are refreshed on every realm until one of the updates sets it
+	ids, err := storage.GetRealmClanIDs("NA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Errorf("unexpected clan ids %v", ids)
+	}
+}
+
+func TestGenericRoundTrip(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = NewStorage()
+
+	err := database.UpdateAppConfiguration("key", []int{1, 2, 3}, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configuration, err := database.GetAppConfiguration[[]int]("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configuration.Value) != 3 {
+		t.Errorf("unexpected configuration value: %v", configuration.Value)
+	}
+
+	err = database.UpdateUserContent("user", "reference", models.UserContentTypePersonalBackground, "https://example.com/image.png", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := database.GetUserContent[string]("user", models.UserContentTypePersonalBackground)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Data != "https://example.com/image.png" {
+		t.Errorf("unexpected content data: %q", content.Data)
+	}
+}
+
+func TestTaskLeases(t *testing.T) {
+	storage := NewStorage()
+
+	err := storage.CreateTasks(models.Task{Type: "test", Status: models.TaskStatusScheduled, ScheduledAfter: time.Now().Add(-time.Minute)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := storage.StartScheduledTasks("worker-a", 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].WorkerID != "worker-a" {
+		t.Fatalf("expected worker-a to claim 1 task, got %+v", claimed)
+	}
+	other, err := storage.StartScheduledTasks("worker-b", 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 0 {
+		t.Errorf("expected claimed task to be skipped, got %d tasks", len(other))
+	}
+
+	restarted, err := storage.RestartAbandonedTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted) != 0 {
+		t.Errorf("expected task with an active lease to stay claimed, got %d restarted", len(restarted))
+	}
+
+	// Expire the lease, the task should be reclaimed by another worker
+	err = storage.RenewTaskLeases("worker-a", -time.Minute, claimed[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted, err = storage.RestartAbandonedTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restarted) != 1 {
+		t.Fatalf("expected 1 restarted task, got %d", len(restarted))
+	}
+	other, err = storage.StartScheduledTasks("worker-b", 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(other) != 1 {
+		t.Fatalf("expected worker-b to claim 1 task, got %d", len(other))
+	}
+
+	// worker-a no longer holds the lease, its result should be discarded
+	stale := claimed[0]
+	stale.Status = models.TaskStatusComplete
+	err = storage.UpdateTasks(stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if storage.tasks[0].Status != models.TaskStatusInProgress || storage.tasks[0].WorkerID != "worker-b" {
+		t.Errorf("expected task to stay claimed by worker-b, got %+v", storage.tasks[0])
+	}
+}
+
+func TestTaskStatusAndCounts(t *testing.T) {
+	storage := NewStorage()
+
+	now := time.Now()
+	err := storage.CreateTasks(
+		models.Task{Type: "a", Status: models.TaskStatusScheduled, CreatedAt: now},
+		models.Task{Type: "a", Status: models.TaskStatusFailed, CreatedAt: now},
+		models.Task{Type: "b", Status: models.TaskStatusComplete, CreatedAt: now.Add(-time.Hour * 48)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := storage.StartScheduledTasks("worker", 1, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 {
+		t.Fatalf("expected 1 claimed task, got %d", len(claimed))
+	}
+	// The status is only changed while it is still the one that was read
+	_, err = storage.SetTaskStatus(claimed[0].ID, models.TaskStatusScheduled, models.TaskStatusCancelled)
+	if err != database.ErrTaskStatusChanged {
+		t.Errorf("expected ErrTaskStatusChanged, got %v", err)
+	}
+	cancelled, err := storage.SetTaskStatus(claimed[0].ID, models.TaskStatusInProgress, models.TaskStatusCancelled)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled.WorkerID != "" {
+		t.Errorf("expected worker lease to be released, got %q", cancelled.WorkerID)
+	}
+
+	// Result from the worker that was processing a cancelled task is discarded
+	claimed[0].Status = models.TaskStatusComplete
+	err = storage.UpdateTasks(claimed[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts, err := storage.CountTasks(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["a"][models.TaskStatusCancelled] != 1 || counts["a"][models.TaskStatusFailed] != 1 {
+		t.Errorf("unexpected counts for a: %v", counts["a"])
+	}
+	if _, ok := counts["b"]; ok {
+		t.Errorf("expected tasks older than since to be excluded, got %v", counts["b"])
+	}
+
+	found, err := storage.FindTasks(models.TaskQuery{Kind: "a", Status: models.TaskStatusFailed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Errorf("expected 1 failed task, got %d", len(found))
+	}
+
+	_, err = storage.SetTaskStatus(primitive.NewObjectID(), models.TaskStatusFailed, models.TaskStatusScheduled)
+	if err != database.ErrTaskNotFound {
+		t.Errorf("expected ErrTaskNotFound, got %v", err)
+	}
+}
+
+func TestTaskClaimOrder(t *testing.T) {
+	storage := NewStorage()
+
+	due := time.Now().Add(-time.Minute)
+	err := storage.CreateTasks(
+		models.Task{Type: "low", Status: models.TaskStatusScheduled, ScheduledAfter: due.Add(-time.Hour), Priority: models.TaskPriorityLow, Data: map[string]any{"realm": "EU"}},
+		models.Task{Type: "high", Status: models.TaskStatusScheduled, ScheduledAfter: due, Priority: models.TaskPriorityHigh, Data: map[string]any{"realm": "EU"}},
+		models.Task{Type: "other-realm", Status: models.TaskStatusScheduled, ScheduledAfter: due, Priority: models.TaskPriorityHigh, Data: map[string]any{"realm": "NA"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claimed, err := storage.StartScheduledTasks("worker", 1, time.Minute, "EU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].Type != "high" {
+		t.Fatalf("expected the high priority task to be claimed first, got %+v", claimed)
+	}
+
+	claimed, err = storage.StartScheduledTasks("worker", 10, time.Minute, "EU")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 1 || claimed[0].Type != "low" {
+		t.Errorf("expected only the remaining EU task to be claimed, got %+v", claimed)
+	}
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) GetSubscriptionByID(id primitive.ObjectID) (models.UserSubscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, subscription := range s.subscriptions {
+		if subscription.ID == id {
+			return subscription, nil
+		}
+	}
+	return models.UserSubscription{}, database.ErrSubscriptionNotFound
+}
+
+func (s *Storage) FindSubscriptionsByUserID(userId string) ([]models.UserSubscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var subscriptions []models.UserSubscription
+	for _, subscription := range s.subscriptions {
+		if subscription.UserID == userId {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreationDate.After(subscriptions[j].CreationDate)
+	})
+	return subscriptions, nil
+}
+
+func (s *Storage) FindSubscriptionsByReferenceIDs(referenceIDs ...string) ([]models.UserSubscription, error) {
+	if len(referenceIDs) == 0 {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var subscriptions []models.UserSubscription
+	for _, subscription := range s.subscriptions {
+		for _, id := range referenceIDs {
+			if subscription.ReferenceID == id {
+				subscriptions = append(subscriptions, subscription)
+				break
+			}
+		}
+	}
+	sort.SliceStable(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreationDate.Before(subscriptions[j].CreationDate)
+	})
+	return subscriptions, nil
+}
+
+func (s *Storage) AddNewUserSubscription(userId string, payload models.UserSubscription) (models.UserSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payload.ID = primitive.NewObjectID()
+	s.subscriptions = append(s.subscriptions, payload)
+	return payload, nil
+}
+
+func (s *Storage) UpdateUserSubscription(id primitive.ObjectID, payload models.SubscriptionUpdate) (models.UserSubscription, error) {
+	s.mu.Lock()
+	for i, subscription := range s.subscriptions {
+		if subscription.ID != id {
+			continue
+		}
+		if payload.UserID != nil {
+			subscription.UserID = *payload.UserID
+		}
+		if payload.ReferenceID != nil {
+			subscription.ReferenceID = *payload.ReferenceID
+		}
+		if payload.Permissions != nil {
+			subscription.Permissions = *payload.Permissions
+		}
+		if payload.Type != nil {
+			subscription.Type = *payload.Type
+		}
+		if payload.ExpiryDate != nil {
+			subscription.ExpiryDate = *payload.ExpiryDate
+		}
+		s.subscriptions[i] = subscription
+		break
+	}
+	s.mu.Unlock()
+
+	return s.GetSubscriptionByID(id)
+}
@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"slices"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (s *Storage) CreateTasks(tasks ...models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, task := range tasks {
+		if task.ID.IsZero() {
+			task.ID = primitive.NewObjectID()
+		}
+		s.tasks = append(s.tasks, task)
+	}
+	return nil
+}
+
+func (s *Storage) UpdateTasks(tasks ...models.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, task := range tasks {
+		for i := range s.tasks {
+			if s.tasks[i].ID == task.ID {
+				if task.WorkerID == "" || s.tasks[i].WorkerID == task.WorkerID {
+					s.tasks[i] = task
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Storage) StartScheduledTasks(workerID string, limit int, lease time.Duration, realms ...string) ([]models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := make([]int, len(s.tasks))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		if s.tasks[a].Priority != s.tasks[b].Priority {
+			return int(s.tasks[b].Priority - s.tasks[a].Priority)
+		}
+		return s.tasks[a].ScheduledAfter.Compare(s.tasks[b].ScheduledAfter)
+	})
+
+	now := time.Now()
+	var tasks []models.Task
+	for _, i := range order {
+		task := s.tasks[i]
+		if len(tasks) >= limit {
+			break
+		}
+		if task.Status != models.TaskStatusScheduled || task.ScheduledAfter.After(now) {
+			continue
+		}
+		if len(realms) > 0 && !slices.Contains(realms, task.Realm()) {
+			continue
+		}
+		s.tasks[i].Status = models.TaskStatusInProgress
+		s.tasks[i].WorkerID = workerID
+		s.tasks[i].LeaseExpiresAt = now.Add(lease)
+		s.tasks[i].LastAttempt = now
+		tasks = append(tasks, s.tasks[i])
+	}
+	return tasks, nil
+}
+
+func (s *Storage) RenewTaskLeases(workerID string, lease time.Duration, ids ...primitive.ObjectID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, task := range s.tasks {
+		if task.Status == models.TaskStatusInProgress && task.WorkerID == workerID && slices.Contains(ids, task.ID) {
+			s.tasks[i].LeaseExpiresAt = time.Now().Add(lease)
+		}
+	}
+	return nil
+}
+
+func (s *Storage) RestartAbandonedTasks() ([]models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var tasks []models.Task
+	for i, task := range s.tasks {
+		if task.Status != models.TaskStatusInProgress {
+			continue
+		}
+		if task.LeaseExpiresAt.IsZero() && task.LastAttempt.After(now.Add(-time.Hour)) || !task.LeaseExpiresAt.IsZero() && task.LeaseExpiresAt.After(now) {
+			continue
+		}
+		s.tasks[i].Status = models.TaskStatusScheduled
+		s.tasks[i].WorkerID = ""
+		s.tasks[i].LeaseExpiresAt = time.Time{}
+		tasks = append(tasks, s.tasks[i])
+	}
+	return tasks, nil
+}
+
+func (s *Storage) GetTaskByID(id primitive.ObjectID) (models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, task := range s.tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return models.Task{}, database.ErrTaskNotFound
+}
+
+func (s *Storage) FindTasks(query models.TaskQuery) ([]models.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tasks []models.Task
+	for _, task := range s.tasks {
+		if query.Kind != "" && task.Type != query.Kind {
+			continue
+		}
+		if query.Status != "" && task.Status != query.Status {
+			continue
+		}
+		if !query.From.IsZero() && task.CreatedAt.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && task.CreatedAt.After(query.To) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	slices.SortStableFunc(tasks, func(a, b models.Task) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if query.Limit > 0 && len(tasks) > query.Limit {
+		tasks = tasks[:query.Limit]
+	}
+	return tasks, nil
+}
+
+func (s *Storage) SetTaskStatus(id primitive.ObjectID, current, status models.TaskStatus) (models.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, task := range s.tasks {
+		if task.ID != id {
+			continue
+		}
+		if task.Status != current {
+			return models.Task{}, database.ErrTaskStatusChanged
+		}
+		s.tasks[i].Status = status
+		s.tasks[i].WorkerID = ""
+		s.tasks[i].LeaseExpiresAt = time.Time{}
+		s.tasks[i].UpdatedAt = time.Now()
+		if status == models.TaskStatusScheduled {
+			s.tasks[i].ScheduledAfter = time.Now()
+			s.tasks[i].Attempts = 0
+			s.tasks[i].FailedTargets = nil
+		}
+		return s.tasks[i], nil
+	}
+	return models.Task{}, database.ErrTaskNotFound
+}
+
+func (s *Storage) CountTasks(since time.Time) (models.TaskCounts, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(models.TaskCounts)
+	for _, task := range s.tasks {
+		if task.CreatedAt.Before(since) {
+			continue
+		}
+		if counts[task.Type] == nil {
+			counts[task.Type] = make(map[models.TaskStatus]int)
+		}
+		counts[task.Type][task.Status]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"errors"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+)
+
+func (s *Storage) GetUserByID(id string) (models.CompleteUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.CompleteUser{}, database.ErrUserNotFound
+	}
+	return s.completeUser(user), nil
+}
+
+func (s *Storage) FindUserByConnection(connectionType models.ConnectionType, externalID string) (models.CompleteUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, connection := range s.connections {
+		if connection.ConnectionType != connectionType || connection.ExternalID != externalID {
+			continue
+		}
+		user, ok := s.users[connection.UserID]
+		if !ok {
+			break
+		}
+		return s.completeUser(user), nil
+	}
+	return models.CompleteUser{}, database.ErrUserNotFound
+}
+
+func (s *Storage) CreateUser(id string) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; ok {
+		return models.User{}, errors.New("duplicate user id")
+	}
+
+	user := models.NewUser(id)
+	s.users[id] = user
+	return user, nil
+}
+
+func (s *Storage) UpdateUser(id string, update models.User) (models.User, error) {
+	update.ID = id
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[id]; ok {
+		s.users[id] = update
+	}
+	return update, nil
+}
+
+func (s *Storage) UpdateUserSessionReset(id string, reset *models.SessionReset) (models.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, database.ErrUserNotFound
+	}
+	if reset != nil {
+		value := *reset
+		reset = &value
+	}
+	user.SessionReset = reset
+	s.users[id] = user
+	return user, nil
+}
+
+func (s *Storage) FindUsersWithSessionReset() ([]models.CompleteUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var users []models.CompleteUser
+	for _, user := range s.users {
+		if user.SessionReset != nil {
+			users = append(users, s.completeUser(user))
+		}
+	}
+	return users, nil
+}
+
+func (s *Storage) completeUser(user models.User) models.CompleteUser {
+	complete := models.CompleteUser{User: user, Connections: []models.UserConnection{}, Subscriptions: []models.UserSubscription{}}
+	for _, connection := range s.connections {
+		if connection.UserID == user.ID {
+			complete.Connections = append(complete.Connections, connection)
+		}
+	}
+	for _, subscription := range s.subscriptions {
+		if subscription.UserID == user.ID {
+			complete.Subscriptions = append(complete.Subscriptions, subscription)
+		}
+	}
+	return complete
+}
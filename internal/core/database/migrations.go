@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+BackfillDefaultConnections marks the oldest connection of each type as default for users that have no default connection.
+Connections created before multiple accounts were supported have no default field, this keeps the account they were using as the default.
+*/
+func BackfillDefaultConnections(db *mongo.Database) error {
+	ctx := context.Background()
+	collection := db.Collection(string(CollectionUserConnections))
+
+	var pipeline mongo.Pipeline
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.M{"_id": 1}}})
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id":        bson.M{"userID": "$userID", "connectionType": "$connectionType"},
+		"oldest":     bson.M{"$first": "$_id"},
+		"hasDefault": bson.M{"$max": bson.M{"$eq": bson.A{"$default", true}}},
+	}}})
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"hasDefault": false}}})
+
+	cur, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	var results []struct {
+		Oldest primitive.ObjectID `bson:"oldest"`
+	}
+	err = cur.All(ctx, &results)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(results))
+	for i, result := range results {
+		ids[i] = result.Oldest
+	}
+	res, err := collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"default": true}})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Int64("updated", res.ModifiedCount).Msg("backfilled default user connections")
+	return nil
+}
@@ -10,6 +10,9 @@ type Account struct {
 
 	Clan *AccountClan `json:"clan" bson:"clan"`
 
+	WN8        *AccountWN8  `json:"wn8,omitempty" bson:"wn8,omitempty"`               // Updated by a background task
+	WN8History []AccountWN8 `json:"wn8History,omitempty" bson:"wn8History,omitempty"` // Capped to the last AccountWN8HistoryLimit values
+
 	LastBattleTime time.Time `json:"lastBattleTime" bson:"lastBattleTime"` // This will probably end up not being updated too often
 
 	LastUpdated time.Time `json:"lastUpdated" bson:"lastUpdated"`
@@ -20,3 +23,12 @@ type AccountClan struct {
 	Role     string    `json:"role" bson:"role"`
 	JoinedAt time.Time `json:"joinedAt" bson:"joinedAt"`
 }
+
+const AccountWN8HistoryLimit = 90
+
+type AccountWN8 struct {
+	Value          int       `json:"value" bson:"value"`
+	Battles        int       `json:"battles" bson:"battles"`
+	LastBattleTime int       `json:"lastBattleTime" bson:"lastBattleTime"`
+	CreatedAt      time.Time `json:"createdAt" bson:"createdAt"`
+}
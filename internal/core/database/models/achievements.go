@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AchievementsSnapshot struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	AccountID int                `json:"accountId" bson:"accountId"`
+	// Snapshots recorded for a custom session reset reference it, realm-wide snapshots have no reference
+	ReferenceID string `json:"referenceId,omitempty" bson:"referenceId,omitempty"`
+
+	Achievements map[string]int `json:"achievements" bson:"achievements"`
+	MaxSeries    map[string]int `json:"maxSeries" bson:"maxSeries"`
+
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+/*
+Diff returns the number of times each achievement was earned since the previous snapshot
+*/
+func (s AchievementsSnapshot) Diff(previous AchievementsSnapshot) map[string]int {
+	earned := make(map[string]int)
+	for id, count := range s.Achievements {
+		if diff := count - previous.Achievements[id]; diff > 0 {
+			earned[id] = diff
+		}
+	}
+	return earned
+}
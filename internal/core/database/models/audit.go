@@ -0,0 +1,126 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditAction string
+
+const (
+	AuditActionContentUpload = AuditAction("content.upload")
+	AuditActionContentRotate = AuditAction("content.rotate")
+
+	AuditActionSubscriptionCreate = AuditAction("subscriptions.create")
+	AuditActionSubscriptionUpdate = AuditAction("subscriptions.update")
+
+	AuditActionConnectionForceUpdate = AuditAction("connections.forceUpdate")
+	AuditActionConnectionRemove      = AuditAction("connections.remove")
+
+	AuditActionRestrictionCreate = AuditAction("restrictions.create")
+	AuditActionRestrictionUpdate = AuditAction("restrictions.update")
+	AuditActionRestrictionDelete = AuditAction("restrictions.delete")
+
+	AuditActionPermissionsUpdate = AuditAction("permissions.update")
+
+	AuditActionTaskCreate = AuditAction("tasks.create")
+	AuditActionTaskRetry  = AuditAction("tasks.retry")
+	AuditActionTaskCancel = AuditAction("tasks.cancel")
+
+	AuditActionScheduleRun = AuditAction("schedules.run")
+)
+
+type AuditLogEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+
+	ActorID      string      `bson:"actorId" json:"actorId"`
+	Action       AuditAction `bson:"action" json:"action"`
+	TargetUserID string      `bson:"targetUserId" json:"targetUserId"` // Optional, not all actions are related to a user
+	TargetID     string      `bson:"targetId" json:"targetId"`
+
+	Changes []AuditChange `bson:"changes" json:"changes"`
+}
+
+/*
+AuditChange is a single top level field that changed, values are encoded as JSON
+*/
+type AuditChange struct {
+	Field  string          `bson:"field" json:"field"`
+	Before json.RawMessage `bson:"before,omitempty" json:"before,omitempty"`
+	After  json.RawMessage `bson:"after,omitempty" json:"after,omitempty"`
+}
+
+type AuditLogQuery struct {
+	ActorID      string
+	TargetUserID string
+	From         time.Time
+	To           time.Time
+	Limit        int
+}
+
+/*
+NewAuditChanges compares the JSON representation of two values, nil is used when there is no value before or after the change.
+Values that do not encode to a JSON object are recorded as a single change with an empty field name.
+*/
+func NewAuditChanges(before, after any) ([]AuditChange, error) {
+	beforeFields, beforeRaw, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, afterRaw, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	if beforeFields == nil && afterFields == nil {
+		if bytes.Equal(beforeRaw, afterRaw) {
+			return nil, nil
+		}
+		return []AuditChange{{Before: beforeRaw, After: afterRaw}}, nil
+	}
+
+	var keys []string
+	for key := range beforeFields {
+		keys = append(keys, key)
+	}
+	for key := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []AuditChange
+	for _, key := range keys {
+		if bytes.Equal(beforeFields[key], afterFields[key]) {
+			continue
+		}
+		changes = append(changes, AuditChange{Field: key, Before: beforeFields[key], After: afterFields[key]})
+	}
+	return changes, nil
+}
+
+func auditFields(value any) (map[string]json.RawMessage, json.RawMessage, error) {
+	if value == nil {
+		return nil, nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(raw) == "null" {
+		return nil, nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil {
+		return nil, raw, nil
+	}
+	return fields, raw, nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestNewAuditChanges(t *testing.T) {
+	before := UserRestriction{UserID: "user", Comment: "spam"}
+	after := UserRestriction{UserID: "user", Comment: "spam", UserMessage: "restricted"}
+
+	changes, err := NewAuditChanges(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Field != "userMessage" || string(changes[0].Before) != `""` || string(changes[0].After) != `"restricted"` {
+		t.Errorf("unexpected changes: %+v", changes)
+	}
+
+	changes, err = NewAuditChanges(nil, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, change := range changes {
+		if change.Before != nil {
+			t.Errorf("expected no before value for %s", change.Field)
+		}
+	}
+
+	changes, err = NewAuditChanges([]string{"a"}, []string{"b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Field != "" || string(changes[0].After) != `["b"]` {
+		t.Errorf("unexpected changes: %+v", changes)
+	}
+
+	changes, err = NewAuditChanges(before, before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+}
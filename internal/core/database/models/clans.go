@@ -1,11 +1,16 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Clan struct {
 	ID       int    `json:"id" bson:"_id"`
 	Tag      string `json:"tag" bson:"tag"`
 	Name     string `json:"name" bson:"name"`
+	Realm    string `json:"realm" bson:"realm"`
 	EmblemID string `json:"emblem" bson:"emblem"`
 
 	Members   []int     `json:"members" bson:"members"`
@@ -13,3 +18,18 @@ type Clan struct {
 
 	LastUpdated time.Time `json:"lastUpdated" bson:"lastUpdated"`
 }
+
+type ClanMemberEventType string
+
+const (
+	ClanMemberEventJoined = ClanMemberEventType("joined")
+	ClanMemberEventLeft   = ClanMemberEventType("left")
+)
+
+type ClanMemberEvent struct {
+	ID        primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Type      ClanMemberEventType `json:"type" bson:"type"`
+	ClanID    int                 `json:"clanId" bson:"clanId"`
+	AccountID int                 `json:"accountId" bson:"accountId"`
+	CreatedAt time.Time           `json:"createdAt" bson:"createdAt"`
+}
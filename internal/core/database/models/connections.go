@@ -10,6 +10,15 @@ const (
 	ConnectionTypeWargaming = ConnectionType("wargaming")
 )
 
+func ParseConnectionType(value string) (ConnectionType, bool) {
+	switch value {
+	case string(ConnectionTypeWargaming):
+		return ConnectionTypeWargaming, true
+	default:
+		return "", false
+	}
+}
+
 type UserConnection struct {
 	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 
@@ -17,6 +26,7 @@ type UserConnection struct {
 	ExternalID     string         `bson:"connectionID" json:"connectionID"`
 	ConnectionType ConnectionType `bson:"connectionType" json:"connectionType"`
 	Permissions    string         `bson:"permissions" json:"permissions"`
+	Default        bool           `bson:"default" json:"default"`
 
 	Metadata map[string]any `bson:"metadata" json:"metadata"`
 }
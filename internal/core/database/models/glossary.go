@@ -13,10 +13,21 @@ type TankAverages struct {
 }
 
 type Achievement struct {
-	ID             string                  `json:"id" bson:"_id"`
-	ImageURL       string                  `json:"image" bson:"image"`
-	Description    string                  `json:"description" bson:"description"`
-	LocalizedNames map[language.Tag]string `json:"localized_names" bson:"localized_names"`
+	ID             string            `json:"id" bson:"_id"`
+	Section        string            `json:"section" bson:"section"`
+	ImageURL       string            `json:"image" bson:"image"`
+	Description    string            `json:"description" bson:"description"`
+	LocalizedNames map[string]string `json:"localized_names" bson:"localized_names"`
+}
+
+func (a Achievement) Name(lang language.Tag) string {
+	if name, ok := a.LocalizedNames[lang.String()]; ok {
+		return name
+	}
+	if name, ok := a.LocalizedNames[language.English.String()]; ok {
+		return name
+	}
+	return a.ID
 }
 
 type vehicleType string
@@ -8,12 +8,20 @@ import (
 )
 
 type RatingSnapshot struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	CreatedAt time.Time          `bson:"createdAt"`
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 
-	SeasonID       int `bson:"seasonId"`
-	AccountID      int `bson:"accountId"`
-	LastBattleTime int `bson:"lastBattleTime"`
+	Realm          string `json:"realm" bson:"realm"`
+	SeasonID       int    `json:"seasonId" bson:"seasonId"`
+	AccountID      int    `json:"accountId" bson:"accountId"`
+	LastBattleTime int    `json:"lastBattleTime" bson:"lastBattleTime"`
+	Final          bool   `json:"final" bson:"final"` // Set on the last snapshot of an account once the season is over
 
-	Stats stats.ReducedStatsFrame `bson:",inline"`
+	Score                  int     `json:"score" bson:"score"`
+	Position               int     `json:"position" bson:"position"`
+	Percentile             float64 `json:"percentile" bson:"percentile"`
+	LeagueIndex            int     `json:"leagueIndex" bson:"leagueIndex"`
+	CalibrationBattlesLeft int     `json:"calibrationBattlesLeft" bson:"calibrationBattlesLeft"`
+
+	Stats stats.ReducedStatsFrame `json:"stats" bson:",inline"`
 }
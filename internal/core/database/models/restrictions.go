@@ -0,0 +1,82 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Restrictions without scopes apply to everything
+const (
+	RestrictionScopeRender      = "render"
+	RestrictionScopeContent     = "content"
+	RestrictionScopeConnections = "connections"
+)
+
+var AllRestrictionScopes = []string{
+	RestrictionScopeRender,
+	RestrictionScopeContent,
+	RestrictionScopeConnections,
+}
+
+type UserRestriction struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    string             `bson:"userID" json:"userID"`
+	CreatedBy string             `bson:"createdBy" json:"createdBy"`
+
+	Scopes      []string `bson:"scopes" json:"scopes"`
+	UserMessage string   `bson:"userMessage" json:"userMessage"`
+	Comment     string   `bson:"comment" json:"comment"`
+
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	ExpiresAt time.Time `bson:"expiresAt" json:"expiresAt"`
+}
+
+type RestrictionUpdate struct {
+	Scopes      *[]string  `bson:"scopes,omitempty" json:"scopes"`
+	UserMessage *string    `bson:"userMessage,omitempty" json:"userMessage"`
+	Comment     *string    `bson:"comment,omitempty" json:"comment"`
+	ExpiresAt   *time.Time `bson:"expiresAt,omitempty" json:"expiresAt"`
+}
+
+func (r UserRestriction) IsExpired() bool {
+	return !r.ExpiresAt.After(time.Now())
+}
+
+/*
+Applies returns true when the restriction is active and covers any of the scopes
+*/
+func (r UserRestriction) Applies(scopes ...string) bool {
+	if r.IsExpired() {
+		return false
+	}
+	if len(r.Scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if slices.Contains(r.Scopes, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Message returns the message shown to a restricted user
+*/
+func (r UserRestriction) Message() string {
+	if r.UserMessage == "" {
+		return "user is restricted"
+	}
+	return r.UserMessage
+}
+
+func ValidRestrictionScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !slices.Contains(AllRestrictionScopes, scope) {
+			return false
+		}
+	}
+	return true
+}
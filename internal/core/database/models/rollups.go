@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/stats"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RollupPeriod string
+
+const (
+	RollupPeriodWeekly  = RollupPeriod("weekly")
+	RollupPeriodMonthly = RollupPeriod("monthly")
+)
+
+var AllRollupPeriods = []RollupPeriod{RollupPeriodWeekly, RollupPeriodMonthly}
+
+/*
+Start returns the beginning of the period containing t in UTC, weeks start on Monday
+*/
+func (p RollupPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case RollupPeriodMonthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+}
+
+/*
+SessionRollup holds the first daily snapshot recorded for an account in a period.
+Snapshots are cumulative, so the difference between two rollups or a rollup and a live session covers all battles in between.
+*/
+type SessionRollup struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Period      RollupPeriod       `bson:"period"`
+	PeriodStart time.Time          `bson:"periodStart"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	RecordedAt  time.Time          `bson:"recordedAt"` // when the snapshot was recorded, stats are accurate as of this time
+	ExpiresAt   time.Time          `bson:"expiresAt"`
+
+	Session stats.SessionSnapshot `bson:",inline"`
+}
+
+/*
+RollupRetention is how long rollups are kept for each period
+*/
+type RollupRetention struct {
+	WeeklyDays  int `bson:"weeklyDays" json:"weeklyDays"`
+	MonthlyDays int `bson:"monthlyDays" json:"monthlyDays"`
+}
+
+func (r RollupRetention) For(period RollupPeriod) time.Duration {
+	switch period {
+	case RollupPeriodMonthly:
+		return time.Hour * 24 * time.Duration(r.MonthlyDays)
+	default:
+		return time.Hour * 24 * time.Duration(r.WeeklyDays)
+	}
+}
+
+/*
+Max returns the longest retention of both for each period
+*/
+func (r RollupRetention) Max(other RollupRetention) RollupRetention {
+	return RollupRetention{WeeklyDays: max(r.WeeklyDays, other.WeeklyDays), MonthlyDays: max(r.MonthlyDays, other.MonthlyDays)}
+}
+
+/*
+RollupRetentionConfiguration sets rollup retention per subscription type, accounts without a listed subscription use Default
+*/
+type RollupRetentionConfiguration struct {
+	Default       RollupRetention                      `bson:"default" json:"default"`
+	Subscriptions map[SubscriptionType]RollupRetention `bson:"subscriptions" json:"subscriptions"`
+}
+
+var DefaultRollupRetention = RollupRetentionConfiguration{
+	Default: RollupRetention{WeeklyDays: 8 * 7, MonthlyDays: 365},
+	Subscriptions: map[SubscriptionType]RollupRetention{
+		SubscriptionTypePlus:    {WeeklyDays: 26 * 7, MonthlyDays: 2 * 365},
+		SubscriptionTypePro:     {WeeklyDays: 52 * 7, MonthlyDays: 5 * 365},
+		SubscriptionTypeProClan: {WeeklyDays: 52 * 7, MonthlyDays: 5 * 365},
+	},
+}
+
+/*
+Retention returns the longest retention for any of the subscription types
+*/
+func (c RollupRetentionConfiguration) Retention(subscriptions ...SubscriptionType) RollupRetention {
+	retention := c.Default
+	for _, s := range subscriptions {
+		if r, ok := c.Subscriptions[s]; ok {
+			retention = retention.Max(r)
+		}
+	}
+	return retention
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRollupPeriodStart(t *testing.T) {
+	// Wednesday
+	now := time.Date(2024, 5, 15, 13, 30, 0, 0, time.UTC)
+	if start := RollupPeriodWeekly.Start(now); !start.Equal(time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected week start %s", start)
+	}
+	// Sunday belongs to the week starting on the previous Monday
+	if start := RollupPeriodWeekly.Start(time.Date(2024, 5, 19, 23, 0, 0, 0, time.UTC)); !start.Equal(time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected week start %s", start)
+	}
+	if start := RollupPeriodMonthly.Start(now); !start.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected month start %s", start)
+	}
+}
+
+func TestRollupRetention(t *testing.T) {
+	config := RollupRetentionConfiguration{
+		Default: RollupRetention{WeeklyDays: 7, MonthlyDays: 0},
+		Subscriptions: map[SubscriptionType]RollupRetention{
+			SubscriptionTypePlus: {WeeklyDays: 14, MonthlyDays: 60},
+			SubscriptionTypePro:  {WeeklyDays: 28, MonthlyDays: 30},
+		},
+	}
+
+	if r := config.Retention(); r != config.Default {
+		t.Errorf("expected default retention, got %+v", r)
+	}
+	if r := config.Retention(SubscriptionTypeSupporter); r != config.Default {
+		t.Errorf("expected default retention, got %+v", r)
+	}
+	if r := config.Retention(SubscriptionTypePlus, SubscriptionTypePro); r.WeeklyDays != 28 || r.MonthlyDays != 60 {
+		t.Errorf("expected the longest retention for each period, got %+v", r)
+	}
+	if d := config.Default.For(RollupPeriodWeekly); d != time.Hour*24*7 {
+		t.Errorf("unexpected weekly retention %s", d)
+	}
+}
@@ -12,6 +12,8 @@ type SessionType string
 const (
 	SessionTypeDaily = SessionType("daily")
 	SessionTypeLive  = SessionType("live")
+	// Daily sessions recorded at a user selected reset time, ReferenceID is set to SessionReset.Reference
+	SessionTypeReset = SessionType("reset")
 )
 
 func ParseSessionType(input string) SessionType {
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskStatus string
+
+const (
+	TaskStatusScheduled  TaskStatus = "TASK_SCHEDULED"
+	TaskStatusInProgress TaskStatus = "TASK_IN_PROGRESS"
+	TaskStatusComplete   TaskStatus = "TASK_COMPLETE"
+	TaskStatusFailed     TaskStatus = "TASK_FAILED"
+	TaskStatusCancelled  TaskStatus = "TASK_CANCELLED"
+	TaskStatusDeadLetter TaskStatus = "TASK_DEAD_LETTER" // Failed on every attempt allowed by the retry policy
+)
+
+func ParseTaskStatus(value string) (TaskStatus, bool) {
+	switch status := TaskStatus(value); status {
+	case TaskStatusScheduled, TaskStatusInProgress, TaskStatusComplete, TaskStatusFailed, TaskStatusCancelled, TaskStatusDeadLetter:
+		return status, true
+	default:
+		return "", false
+	}
+}
+
+/*
+TaskPriority orders scheduled tasks, tasks with a higher priority are claimed first
+*/
+type TaskPriority int
+
+const (
+	TaskPriorityLow    TaskPriority = 0
+	TaskPriorityNormal TaskPriority = 50
+	TaskPriorityHigh   TaskPriority = 100
+)
+
+type Task struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type      string             `bson:"kind" json:"kind"`
+	CreatedAt time.Time          `bson:"created_at" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updatedAt"`
+
+	Targets []int `bson:"targets" json:"targets"`
+
+	Logs []AttemptLog `bson:"logs" json:"logs"`
+
+	Status         TaskStatus   `bson:"status" json:"status"`
+	Priority       TaskPriority `bson:"priority" json:"priority"`
+	ScheduledAfter time.Time    `bson:"scheduled_after" json:"scheduledAfter"`
+	LastAttempt    time.Time    `bson:"last_attempt" json:"lastAttempt"`
+	Attempts       int          `bson:"attempts" json:"attempts"`
+	FailedTargets  []int        `bson:"failed_targets" json:"failedTargets"` // Targets that were still failing when the task was given up on
+
+	// Set when a worker claims the task, the task can be reclaimed by another worker once the lease expires
+	WorkerID       string    `bson:"worker_id" json:"workerId"`
+	LeaseExpiresAt time.Time `bson:"lease_expires_at" json:"leaseExpiresAt"`
+
+	Data map[string]any `bson:"data" json:"data"`
+}
+
+/*
+Realm returns the realm a task is targeting, tasks that are not tied to a realm return an empty string
+*/
+func (t Task) Realm() string {
+	realm, _ := t.Data["realm"].(string)
+	return realm
+}
+
+func (t *Task) LogAttempt(log AttemptLog) {
+	t.Logs = append(t.Logs, log)
+}
+
+func (t *Task) OnCreated() {
+	t.LastAttempt = time.Now()
+	t.CreatedAt = time.Now()
+	t.UpdatedAt = time.Now()
+}
+func (t *Task) OnUpdated() {
+	t.UpdatedAt = time.Now()
+}
+
+type AttemptLog struct {
+	Targets   []int     `json:"targets" bson:"targets"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
+	Comment   string    `json:"result" bson:"result"`
+	Error     string    `json:"error" bson:"error"`
+}
+
+type TaskQuery struct {
+	Kind   string
+	Status TaskStatus
+	From   time.Time
+	To     time.Time
+	Limit  int
+}
+
+/*
+TaskCounts is the number of tasks per kind and status
+*/
+type TaskCounts map[string]map[TaskStatus]int
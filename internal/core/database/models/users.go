@@ -1,6 +1,12 @@
 package models
 
-import "github.com/cufee/aftermath-core/permissions/v2"
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cufee/aftermath-core/permissions/v2"
+)
 
 type featureFlag string
 
@@ -13,6 +19,8 @@ type User struct {
 
 	FeatureFlags []featureFlag `bson:"featureFlags" json:"featureFlags"`
 	Permissions  string        `bson:"permissions" json:"permissions"`
+
+	SessionReset *SessionReset `bson:"sessionReset,omitempty" json:"sessionReset,omitempty"`
 }
 
 func NewUser(id string) User {
@@ -31,6 +39,58 @@ func (u *User) HasFeatureFlag(flag featureFlag) bool {
 	return false
 }
 
+var ErrInvalidSessionReset = errors.New("invalid session reset")
+
+/*
+SessionReset is a user selected local hour at which daily sessions start, overriding the realm-wide reset
+*/
+type SessionReset struct {
+	Hour     int    `bson:"hour" json:"hour"`
+	Timezone string `bson:"timezone" json:"timezone"`
+}
+
+func (r SessionReset) Validate() error {
+	if r.Hour < 0 || r.Hour > 23 {
+		return fmt.Errorf("%w: hour must be between 0 and 23", ErrInvalidSessionReset)
+	}
+	if _, err := time.LoadLocation(r.Timezone); err != nil || r.Timezone == "" {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidSessionReset, r.Timezone)
+	}
+	return nil
+}
+
+/*
+Reference is used as a reference ID on session snapshots recorded at this reset time, it does not change with DST so that snapshots can be shared between users
+*/
+func (r SessionReset) Reference() string {
+	return fmt.Sprintf("reset/%s/%02d", r.Timezone, r.Hour)
+}
+
+/*
+Last returns the most recent reset at or before now, invalid timezones fall back to UTC
+*/
+func (r SessionReset) Last(now time.Time) time.Time {
+	location, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	local := now.In(location)
+	reset := time.Date(local.Year(), local.Month(), local.Day(), r.Hour, 0, 0, 0, location)
+	if reset.After(now) {
+		reset = time.Date(local.Year(), local.Month(), local.Day()-1, r.Hour, 0, 0, 0, location)
+	}
+	return reset
+}
+
+/*
+Due returns true when a reset happens within the hour starting at hour, resets in timezones with a partial hour offset are due at the start of that hour
+*/
+func (r SessionReset) Due(hour time.Time) bool {
+	hour = hour.Truncate(time.Hour)
+	return !r.Last(hour.Add(time.Hour - time.Nanosecond)).Before(hour)
+}
+
 type CompleteUser struct {
 	User          `bson:",inline" json:",inline"`
 	Subscriptions []UserSubscription `bson:"subscriptions" json:"subscriptions"`
@@ -38,25 +98,71 @@ type CompleteUser struct {
 }
 
 func (u CompleteUser) Permissions() permissions.Permissions {
-	perms := permissions.Parse(u.User.Permissions) | permissions.User
+	perms := permissions.Blank
+	for _, source := range u.PermissionSources() {
+		perms = perms.Add(source.Permissions)
+	}
+	return perms
+}
+
+type PermissionSourceType string
+
+const (
+	PermissionSourceDefault      = PermissionSourceType("default")
+	PermissionSourceUser         = PermissionSourceType("user")
+	PermissionSourceConnection   = PermissionSourceType("connection")
+	PermissionSourceSubscription = PermissionSourceType("subscription")
+)
+
+type PermissionSource struct {
+	Type        PermissionSourceType    `json:"type"`
+	ID          string                  `json:"id"`
+	Permissions permissions.Permissions `json:"permissions"`
+}
+
+/*
+PermissionSources returns every source that contributes to Permissions, all users get the default user role
+*/
+func (u CompleteUser) PermissionSources() []PermissionSource {
+	sources := []PermissionSource{
+		{Type: PermissionSourceDefault, ID: "roles/user", Permissions: permissions.User},
+		{Type: PermissionSourceUser, ID: u.ID, Permissions: permissions.Parse(u.User.Permissions)},
+	}
 	for _, c := range u.Connections {
-		perms = perms.Add(permissions.Parse(c.Permissions))
+		sources = append(sources, PermissionSource{Type: PermissionSourceConnection, ID: c.ID.Hex(), Permissions: permissions.Parse(c.Permissions)})
 	}
 	for _, s := range u.Subscriptions {
-		perms = perms.Add(permissions.Parse(s.Permissions))
+		sources = append(sources, PermissionSource{Type: PermissionSourceSubscription, ID: s.ID.Hex(), Permissions: permissions.Parse(s.Permissions)})
 	}
-	return perms
+	return sources
 }
 
+/*
+Connection returns the default connection of a given type, falling back to the first one found
+*/
 func (u CompleteUser) Connection(connectionType ConnectionType) *UserConnection {
-	for _, c := range u.Connections {
-		if c.ConnectionType == connectionType {
+	connections := u.ConnectionsByType(connectionType)
+	for _, c := range connections {
+		if c.Default {
 			return &c
 		}
 	}
+	if len(connections) > 0 {
+		return &connections[0]
+	}
 	return nil
 }
 
+func (u CompleteUser) ConnectionsByType(connectionType ConnectionType) []UserConnection {
+	var connections []UserConnection
+	for _, c := range u.Connections {
+		if c.ConnectionType == connectionType {
+			connections = append(connections, c)
+		}
+	}
+	return connections
+}
+
 func (u CompleteUser) Subscription(subscriptionType SubscriptionType) *UserSubscription {
 	for _, s := range u.Subscriptions {
 		if s.Type == subscriptionType {
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionResetDue(t *testing.T) {
+	reset := SessionReset{Hour: 4, Timezone: "Europe/Berlin"}
+	if err := reset.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 04:00 in Berlin is 02:00 UTC in summer and 03:00 UTC in winter
+	summer := time.Date(2024, 7, 1, 2, 0, 0, 0, time.UTC)
+	if !reset.Due(summer) || reset.Due(summer.Add(time.Hour)) {
+		t.Errorf("expected reset to be due at 02:00 UTC in summer")
+	}
+	winter := time.Date(2024, 12, 1, 3, 0, 0, 0, time.UTC)
+	if !reset.Due(winter) || reset.Due(winter.Add(-time.Hour)) {
+		t.Errorf("expected reset to be due at 03:00 UTC in winter")
+	}
+
+	if last := reset.Last(summer.Add(-time.Minute)); !last.Equal(summer.Add(-time.Hour * 24)) {
+		t.Errorf("expected the previous reset to be a day earlier, got %s", last)
+	}
+
+	// Partial hour offsets are due at the start of the hour
+	india := SessionReset{Hour: 0, Timezone: "Asia/Kolkata"}
+	if !india.Due(time.Date(2024, 7, 1, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected reset to be due at 18:00 UTC")
+	}
+
+	for _, invalid := range []SessionReset{{Hour: 24, Timezone: "UTC"}, {Hour: 1, Timezone: "Mars/Olympus"}, {Hour: 1}} {
+		if invalid.Validate() == nil {
+			t.Errorf("expected %+v to be invalid", invalid)
+		}
+	}
+}
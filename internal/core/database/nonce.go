@@ -15,7 +15,11 @@ var (
 )
 
 func NewNonce(referenceID string, duration time.Duration) (string, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.NewNonce(referenceID, duration)
+}
+
+func (c *Client) NewNonce(referenceID string, duration time.Duration) (string, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var nonce models.Nonce
@@ -23,7 +27,7 @@ func NewNonce(referenceID string, duration time.Duration) (string, error) {
 	nonce.ExpiresAt = time.Now().Add(duration)
 	nonce.CreatedAt = time.Now()
 
-	res, err := DefaultClient.Collection(CollectionNonce).InsertOne(ctx, nonce)
+	res, err := c.Collection(CollectionNonce).InsertOne(ctx, nonce)
 	if err != nil {
 		return "", err
 	}
@@ -37,7 +41,11 @@ func NewNonce(referenceID string, duration time.Duration) (string, error) {
 }
 
 func GetNonceByID(id string) (models.Nonce, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetNonceByID(id)
+}
+
+func (c *Client) GetNonceByID(id string) (models.Nonce, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -46,7 +54,7 @@ func GetNonceByID(id string) (models.Nonce, error) {
 	}
 
 	var nonce models.Nonce
-	err = DefaultClient.Collection(CollectionNonce).FindOne(ctx, bson.M{"_id": oid, "expiresAt": bson.M{"$gt": time.Now()}}).Decode(&nonce)
+	err = c.Collection(CollectionNonce).FindOne(ctx, bson.M{"_id": oid, "expiresAt": bson.M{"$gt": time.Now()}}).Decode(&nonce)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nonce, ErrNonceNotFound
@@ -58,7 +66,11 @@ func GetNonceByID(id string) (models.Nonce, error) {
 }
 
 func ExpireNonceByID(id string) error {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.ExpireNonceByID(id)
+}
+
+func (c *Client) ExpireNonceByID(id string) error {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -66,7 +78,7 @@ func ExpireNonceByID(id string) error {
 		return err
 	}
 
-	_, err = DefaultClient.Collection(CollectionNonce).UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"expiresAt": time.Now()}})
+	_, err = c.Collection(CollectionNonce).UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"expiresAt": time.Now()}})
 	if err != nil {
 		return err
 	}
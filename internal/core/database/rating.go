@@ -0,0 +1,169 @@
+package database
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func InsertRatingSnapshots(snapshots ...models.RatingSnapshot) error {
+	return DefaultStorage.InsertRatingSnapshots(snapshots...)
+}
+
+func (c *Client) InsertRatingSnapshots(snapshots ...models.RatingSnapshot) error {
+	var inserts []mongo.WriteModel
+	for _, snapshot := range snapshots {
+		inserts = append(inserts, mongo.NewInsertOneModel().SetDocument(snapshot))
+	}
+	if len(inserts) == 0 {
+		return nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionRatingSeasonSnapshots).BulkWrite(ctx, inserts)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+GetRatingSnapshots returns all snapshots recorded for an account during a season, oldest first.
+*/
+func GetRatingSnapshots(accountID, seasonID int) ([]models.RatingSnapshot, error) {
+	return DefaultStorage.GetRatingSnapshots(accountID, seasonID)
+}
+
+func (c *Client) GetRatingSnapshots(accountID, seasonID int) ([]models.RatingSnapshot, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.M{"createdAt": 1})
+
+	var snapshots []models.RatingSnapshot
+	cur, err := c.Collection(CollectionRatingSeasonSnapshots).Find(ctx, bson.M{"accountId": accountID, "seasonId": seasonID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, cur.All(ctx, &snapshots)
+}
+
+/*
+GetFinalRatingSnapshots returns the final snapshot of each finished season for an account, latest season first.
+*/
+func GetFinalRatingSnapshots(accountID int) ([]models.RatingSnapshot, error) {
+	return DefaultStorage.GetFinalRatingSnapshots(accountID)
+}
+
+func (c *Client) GetFinalRatingSnapshots(accountID int) ([]models.RatingSnapshot, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.M{"seasonId": -1})
+
+	var snapshots []models.RatingSnapshot
+	cur, err := c.Collection(CollectionRatingSeasonSnapshots).Find(ctx, bson.M{"accountId": accountID, "final": true}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, cur.All(ctx, &snapshots)
+}
+
+/*
+GetLastRatingSnapshots returns the latest snapshot recorded during a season for each account.
+*/
+func GetLastRatingSnapshots(seasonID int, accountIDs ...int) (map[int]models.RatingSnapshot, error) {
+	return DefaultStorage.GetLastRatingSnapshots(seasonID, accountIDs...)
+}
+
+func (c *Client) GetLastRatingSnapshots(seasonID int, accountIDs ...int) (map[int]models.RatingSnapshot, error) {
+	snapshots := make(map[int]models.RatingSnapshot)
+	if len(accountIDs) == 0 {
+		return snapshots, nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var pipeline mongo.Pipeline
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"accountId": bson.M{"$in": accountIDs}, "seasonId": seasonID}}})
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.M{"createdAt": 1}}})
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{"_id": "$accountId", "snapshot": bson.M{"$last": "$$ROOT"}}}})
+
+	cur, err := c.Collection(CollectionRatingSeasonSnapshots).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Snapshot models.RatingSnapshot `bson:"snapshot"`
+	}
+	err = cur.All(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		snapshots[result.Snapshot.AccountID] = result.Snapshot
+	}
+	return snapshots, nil
+}
+
+/*
+CloseRatingSeasons marks the latest snapshot of each account as final for all seasons on a realm that are older than currentSeasonID.
+  - Seasons that were already closed for an account are not updated again.
+*/
+func CloseRatingSeasons(realm string, currentSeasonID int) (int, error) {
+	return DefaultStorage.CloseRatingSeasons(realm, currentSeasonID)
+}
+
+func (c *Client) CloseRatingSeasons(realm string, currentSeasonID int) (int, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var pipeline mongo.Pipeline
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"realm": realm, "seasonId": bson.M{"$lt": currentSeasonID}}}})
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.M{"createdAt": 1}}})
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{
+		"_id":    bson.M{"accountId": "$accountId", "seasonId": "$seasonId"},
+		"closed": bson.M{"$max": "$final"},
+		"lastId": bson.M{"$last": "$_id"},
+	}}})
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"closed": false}}})
+
+	cur, err := c.Collection(CollectionRatingSeasonSnapshots).Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	var results []struct {
+		LastID primitive.ObjectID `bson:"lastId"`
+	}
+	err = cur.All(ctx, &results)
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	var ids []primitive.ObjectID
+	for _, result := range results {
+		ids = append(ids, result.LastID)
+	}
+
+	result, err := c.Collection(CollectionRatingSeasonSnapshots).UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": bson.M{"final": true}})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
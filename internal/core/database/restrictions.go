@@ -0,0 +1,129 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrRestrictionNotFound = errors.New("restriction not found")
+
+func GetUserRestriction(id primitive.ObjectID) (models.UserRestriction, error) {
+	return DefaultStorage.GetUserRestriction(id)
+}
+
+func (c *Client) GetUserRestriction(id primitive.ObjectID) (models.UserRestriction, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var restriction models.UserRestriction
+	err := c.Collection(CollectionUserRestrictions).FindOne(ctx, bson.M{"_id": id}).Decode(&restriction)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return restriction, ErrRestrictionNotFound
+		}
+		return restriction, err
+	}
+
+	return restriction, nil
+}
+
+func FindUserRestrictions(userId string) ([]models.UserRestriction, error) {
+	return DefaultStorage.FindUserRestrictions(userId)
+}
+
+func (c *Client) FindUserRestrictions(userId string) ([]models.UserRestriction, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var restrictions []models.UserRestriction
+	opts := options.Find().SetSort(bson.M{"createdAt": -1})
+	cur, err := c.Collection(CollectionUserRestrictions).Find(ctx, bson.M{"userID": userId}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return restrictions, cur.All(ctx, &restrictions)
+}
+
+/*
+FindActiveUserRestriction returns the first active restriction of a user that covers any of the scopes, or nil
+*/
+func FindActiveUserRestriction(userId string, scopes ...string) (*models.UserRestriction, error) {
+	restrictions, err := FindUserRestrictions(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, restriction := range restrictions {
+		if restriction.Applies(scopes...) {
+			return &restriction, nil
+		}
+	}
+	return nil, nil
+}
+
+func AddUserRestriction(payload models.UserRestriction) (models.UserRestriction, error) {
+	return DefaultStorage.AddUserRestriction(payload)
+}
+
+func (c *Client) AddUserRestriction(payload models.UserRestriction) (models.UserRestriction, error) {
+	payload.ID = primitive.NilObjectID // Ensure ID is empty
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	result, err := c.Collection(CollectionUserRestrictions).InsertOne(ctx, payload)
+	if err != nil {
+		return models.UserRestriction{}, err
+	}
+
+	id, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return models.UserRestriction{}, errors.New("invalid inserted id")
+	}
+
+	payload.ID = id
+	return payload, nil
+}
+
+func UpdateUserRestriction(id primitive.ObjectID, payload models.RestrictionUpdate) (models.UserRestriction, error) {
+	return DefaultStorage.UpdateUserRestriction(id, payload)
+}
+
+func (c *Client) UpdateUserRestriction(id primitive.ObjectID, payload models.RestrictionUpdate) (models.UserRestriction, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	result, err := c.Collection(CollectionUserRestrictions).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": payload})
+	if err != nil {
+		return models.UserRestriction{}, err
+	}
+	if result.MatchedCount == 0 {
+		return models.UserRestriction{}, ErrRestrictionNotFound
+	}
+
+	return c.GetUserRestriction(id)
+}
+
+func DeleteUserRestriction(id primitive.ObjectID) error {
+	return DefaultStorage.DeleteUserRestriction(id)
+}
+
+func (c *Client) DeleteUserRestriction(id primitive.ObjectID) error {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	result, err := c.Collection(CollectionUserRestrictions).DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrRestrictionNotFound
+	}
+	return nil
+}
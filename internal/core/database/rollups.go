@@ -0,0 +1,124 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrNoSessionRollup = errors.New("no session rollup found")
+)
+
+func rollupCollection(period models.RollupPeriod) collectionName {
+	if period == models.RollupPeriodMonthly {
+		return CollectionSessionRollupsMonthly
+	}
+	return CollectionSessionRollupsWeekly
+}
+
+/*
+GetLatestSessionSnapshots returns the most recent realm-wide snapshot of sessionType for each account, snapshots recorded with a reference ID are ignored
+*/
+func GetLatestSessionSnapshots(sessionType models.SessionType, accountIDs ...int) (map[int]models.Snapshot, error) {
+	return DefaultStorage.GetLatestSessionSnapshots(sessionType, accountIDs...)
+}
+
+func (c *Client) GetLatestSessionSnapshots(sessionType models.SessionType, accountIDs ...int) (map[int]models.Snapshot, error) {
+	snapshots := make(map[int]models.Snapshot)
+	if len(accountIDs) == 0 {
+		return snapshots, nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var pipeline mongo.Pipeline
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"accountId": bson.M{"$in": accountIDs}, "type": sessionType, "referenceId": ""}}})
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}})
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{"_id": "$accountId", "snapshot": bson.M{"$first": "$$ROOT"}}}})
+	pipeline = append(pipeline, bson.D{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$snapshot"}}})
+
+	cur, err := c.Collection(CollectionSessions).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.Snapshot
+	err = cur.All(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range results {
+		snapshots[snapshot.Session.AccountID] = snapshot
+	}
+	return snapshots, nil
+}
+
+/*
+UpsertSessionRollups creates a rollup for each account and period if one does not exist yet, existing rollups only have their expiration extended
+*/
+func UpsertSessionRollups(rollups ...models.SessionRollup) error {
+	return DefaultStorage.UpsertSessionRollups(rollups...)
+}
+
+func (c *Client) UpsertSessionRollups(rollups ...models.SessionRollup) error {
+	writes := make(map[models.RollupPeriod][]mongo.WriteModel)
+	for _, rollup := range rollups {
+		model := mongo.NewUpdateOneModel()
+		model.SetFilter(bson.M{"accountId": rollup.Session.AccountID, "periodStart": rollup.PeriodStart})
+		model.SetUpdate(bson.M{
+			"$setOnInsert": bson.M{
+				"period":         rollup.Period,
+				"periodStart":    rollup.PeriodStart,
+				"createdAt":      time.Now(),
+				"recordedAt":     rollup.RecordedAt,
+				"lastBattleTime": rollup.Session.LastBattleTime,
+				"global":         rollup.Session.Global,
+				"rating":         rollup.Session.Rating,
+				"vehicles":       rollup.Session.Vehicles,
+			},
+			"$max": bson.M{"expiresAt": rollup.ExpiresAt},
+		})
+		model.SetUpsert(true)
+		writes[rollup.Period] = append(writes[rollup.Period], model)
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	for period, batch := range writes {
+		_, err := c.Collection(rollupCollection(period)).BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+GetSessionRollup returns the latest rollup for an account which was recorded at or before the given time
+*/
+func GetSessionRollup(period models.RollupPeriod, accountID int, before time.Time) (models.SessionRollup, error) {
+	return DefaultStorage.GetSessionRollup(period, accountID, before)
+}
+
+func (c *Client) GetSessionRollup(period models.RollupPeriod, accountID int, before time.Time) (models.SessionRollup, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var rollup models.SessionRollup
+	err := c.Collection(rollupCollection(period)).FindOne(ctx, bson.M{"accountId": accountID, "recordedAt": bson.M{"$lte": before}}, options.FindOne().SetSort(bson.M{"recordedAt": -1})).Decode(&rollup)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return rollup, ErrNoSessionRollup
+		}
+		return rollup, err
+	}
+	return rollup, nil
+}
@@ -18,17 +18,22 @@ var (
 type SessionGetOptions struct {
 	LastBattleBefore *int
 	LastBattleAfter  *int
+	CreatedBefore    *time.Time
 	ReferenceID      *string
 	Type             models.SessionType
 }
 
 func GetPlayerSessionSnapshot(accountID int, o ...SessionGetOptions) (models.Snapshot, error) {
+	return DefaultStorage.GetPlayerSessionSnapshot(accountID, o...)
+}
+
+func (c *Client) GetPlayerSessionSnapshot(accountID int, o ...SessionGetOptions) (models.Snapshot, error) {
 	opts := SessionGetOptions{Type: models.SessionTypeDaily}
 	if len(o) > 0 {
 		opts = o[0]
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	findOptions := options.FindOne()
@@ -41,15 +46,22 @@ func GetPlayerSessionSnapshot(accountID int, o ...SessionGetOptions) (models.Sna
 	if opts.ReferenceID != nil {
 		query["referenceId"] = opts.ReferenceID
 	}
-	if opts.LastBattleBefore != nil {
-		query["lastBattleTime"] = bson.M{"$lt": *opts.LastBattleBefore}
+	if opts.LastBattleBefore != nil || opts.LastBattleAfter != nil {
+		lastBattleTime := bson.M{}
+		if opts.LastBattleBefore != nil {
+			lastBattleTime["$lt"] = *opts.LastBattleBefore
+		}
+		if opts.LastBattleAfter != nil {
+			lastBattleTime["$gt"] = *opts.LastBattleAfter
+		}
+		query["lastBattleTime"] = lastBattleTime
 	}
-	if opts.LastBattleAfter != nil {
-		query["lastBattleTime"] = bson.M{"$gt": *opts.LastBattleAfter}
+	if opts.CreatedBefore != nil {
+		query["createdAt"] = bson.M{"$lte": *opts.CreatedBefore}
 	}
 
 	var snapshot models.Snapshot
-	err := DefaultClient.Collection(CollectionSessions).FindOne(ctx, query, findOptions).Decode(&snapshot)
+	err := c.Collection(CollectionSessions).FindOne(ctx, query, findOptions).Decode(&snapshot)
 	if err != nil {
 		if errors.Is(mongo.ErrNoDocuments, err) {
 			return snapshot, ErrNoSessionCache
@@ -61,11 +73,15 @@ func GetPlayerSessionSnapshot(accountID int, o ...SessionGetOptions) (models.Sna
 }
 
 func GetLastBattleTimes(sessionType models.SessionType, referenceId *string, accountIDs ...int) (map[int]int, error) {
+	return DefaultStorage.GetLastBattleTimes(sessionType, referenceId, accountIDs...)
+}
+
+func (c *Client) GetLastBattleTimes(sessionType models.SessionType, referenceId *string, accountIDs ...int) (map[int]int, error) {
 	if len(accountIDs) == 0 {
 		return make(map[int]int), nil
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	filter := bson.M{"accountId": bson.M{"$in": accountIDs}, "type": sessionType}
@@ -77,7 +93,7 @@ func GetLastBattleTimes(sessionType models.SessionType, referenceId *string, acc
 	pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
 	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{"_id": "$accountId", "lastBattleTime": bson.M{"$max": "$lastBattleTime"}}}})
 	pipeline = append(pipeline, bson.D{{Key: "$project", Value: bson.M{"_id": 0, "accountId": "$_id", "lastBattleTime": 1}}})
-	cur, err := DefaultClient.Collection(CollectionSessions).Aggregate(ctx, pipeline)
+	cur, err := c.Collection(CollectionSessions).Aggregate(ctx, pipeline)
 	if err != nil {
 		if errors.Is(mongo.ErrNoDocuments, err) {
 			return lastBattles, nil
@@ -101,6 +117,10 @@ func GetLastBattleTimes(sessionType models.SessionType, referenceId *string, acc
 }
 
 func InsertSession(sessionType models.SessionType, referenceId *string, sessions ...stats.SessionSnapshot) error {
+	return DefaultStorage.InsertSession(sessionType, referenceId, sessions...)
+}
+
+func (c *Client) InsertSession(sessionType models.SessionType, referenceId *string, sessions ...stats.SessionSnapshot) error {
 	var sessionInserts []mongo.WriteModel
 	for _, session := range sessions {
 		model := mongo.NewInsertOneModel()
@@ -119,10 +139,10 @@ func InsertSession(sessionType models.SessionType, referenceId *string, sessions
 		return nil
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionSessions).BulkWrite(ctx, sessionInserts)
+	_, err := c.Collection(CollectionSessions).BulkWrite(ctx, sessionInserts)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,181 @@
+package database
+
+import (
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/stats"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AccountsRepository interface {
+	UpdatePlayerAccounts(accounts ...models.Account) error
+	UpdateAccountsWN8(values map[int]models.AccountWN8) error
+	GetPlayerAccount(id int) (models.Account, error)
+	GetRealmAccountIDs(realm string) ([]int, error)
+}
+
+type ClansRepository interface {
+	GetClans(clanIDs ...int) (map[int]models.Clan, error)
+	GetRealmClanIDs(realm string) ([]int, error)
+	UpdateClans(clans ...models.Clan) error
+	InsertClanMemberEvents(events ...models.ClanMemberEvent) error
+}
+
+type SessionsRepository interface {
+	GetPlayerSessionSnapshot(accountID int, o ...SessionGetOptions) (models.Snapshot, error)
+	GetLastBattleTimes(sessionType models.SessionType, referenceId *string, accountIDs ...int) (map[int]int, error)
+	InsertSession(sessionType models.SessionType, referenceId *string, sessions ...stats.SessionSnapshot) error
+	GetLatestSessionSnapshots(sessionType models.SessionType, accountIDs ...int) (map[int]models.Snapshot, error)
+}
+
+type RollupsRepository interface {
+	UpsertSessionRollups(rollups ...models.SessionRollup) error
+	GetSessionRollup(period models.RollupPeriod, accountID int, before time.Time) (models.SessionRollup, error)
+}
+
+type SnapshotsRepository interface {
+	InsertAchievementsSnapshots(snapshots ...models.AchievementsSnapshot) error
+	GetLastAchievementsSnapshot(accountID int, referenceID string) (models.AchievementsSnapshot, error)
+
+	InsertRatingSnapshots(snapshots ...models.RatingSnapshot) error
+	GetRatingSnapshots(accountID, seasonID int) ([]models.RatingSnapshot, error)
+	GetFinalRatingSnapshots(accountID int) ([]models.RatingSnapshot, error)
+	GetLastRatingSnapshots(seasonID int, accountIDs ...int) (map[int]models.RatingSnapshot, error)
+	CloseRatingSeasons(realm string, currentSeasonID int) (int, error)
+}
+
+type UsersRepository interface {
+	GetUserByID(id string) (models.CompleteUser, error)
+	FindUserByConnection(connectionType models.ConnectionType, externalID string) (models.CompleteUser, error)
+	CreateUser(id string) (models.User, error)
+	UpdateUser(id string, update models.User) (models.User, error)
+	UpdateUserSessionReset(id string, reset *models.SessionReset) (models.User, error)
+	FindUsersWithSessionReset() ([]models.CompleteUser, error)
+}
+
+type ConnectionsRepository interface {
+	FindUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error)
+	FindConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType) ([]models.UserConnection, error)
+	FindConnectionsByReferenceIDs(connectionType models.ConnectionType, referenceIDs ...string) ([]models.UserConnection, error)
+	GetUserConnections(userId string) ([]models.UserConnection, error)
+	GetUserConnection(userId string, connectionType models.ConnectionType) (models.UserConnection, error)
+	GetUserConnectionByExternalID(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error)
+	AddUserConnection(userId string, connectionType models.ConnectionType, externalID string, metadata map[string]any) (models.UserConnection, error)
+	UpdateUserConnection(userId string, connectionType models.ConnectionType, externalID string, payload models.ConnectionUpdate) (models.UserConnection, error)
+	SetDefaultUserConnection(userId string, connectionType models.ConnectionType, externalID string) (models.UserConnection, error)
+	DeleteUserConnections(userId string, connectionType models.ConnectionType, externalIDs ...string) ([]models.UserConnection, error)
+	UpdateManyConnectionsByReferenceID(referenceId string, connectionType models.ConnectionType, payload models.ConnectionUpdate) error
+}
+
+type SubscriptionsRepository interface {
+	GetSubscriptionByID(id primitive.ObjectID) (models.UserSubscription, error)
+	FindSubscriptionsByUserID(userId string) ([]models.UserSubscription, error)
+	FindSubscriptionsByReferenceIDs(referenceIDs ...string) ([]models.UserSubscription, error)
+	AddNewUserSubscription(userId string, payload models.UserSubscription) (models.UserSubscription, error)
+	UpdateUserSubscription(id primitive.ObjectID, payload models.SubscriptionUpdate) (models.UserSubscription, error)
+}
+
+type RestrictionsRepository interface {
+	GetUserRestriction(id primitive.ObjectID) (models.UserRestriction, error)
+	FindUserRestrictions(userId string) ([]models.UserRestriction, error)
+	AddUserRestriction(payload models.UserRestriction) (models.UserRestriction, error)
+	UpdateUserRestriction(id primitive.ObjectID, payload models.RestrictionUpdate) (models.UserRestriction, error)
+	DeleteUserRestriction(id primitive.ObjectID) error
+}
+
+/*
+ContentRepository stores user content with data encoded as a raw bson value, package level functions decode it into a concrete type.
+*/
+type ContentRepository interface {
+	UpdateUserContent(userID, referenceID string, contentType models.UserContentType, data bson.RawValue, metadata map[string]any, upsert bool) error
+	UpdateUserContentReferenceID(userID string, contentType models.UserContentType, newReferenceID string) (models.UserContent[bson.RawValue], error)
+	GetUserContent(userID string, contentType ...models.UserContentType) (models.UserContent[bson.RawValue], error)
+	GetContentByReferenceIDs(referenceIDs []string, contentType ...models.UserContentType) ([]models.UserContent[bson.RawValue], error)
+	DeleteUserContentByReferenceIDs(userID string, referenceIDs []string, contentType ...models.UserContentType) error
+}
+
+type GlossaryRepository interface {
+	UpdateAverages(averages map[int]stats.ReducedStatsFrame) error
+	GetVehicleAverages(vehicleIDs ...int) (map[int]stats.ReducedStatsFrame, error)
+	GetGlossaryVehicles(vehicleIDs ...int) (map[int]models.Vehicle, error)
+	UpdateGlossary(vehicles []models.Vehicle) error
+	GetGlossaryAchievements(achievementIDs ...string) (map[string]models.Achievement, error)
+	UpdateAchievementsGlossary(achievements []models.Achievement) error
+}
+
+type NonceRepository interface {
+	NewNonce(referenceID string, duration time.Duration) (string, error)
+	GetNonceByID(id string) (models.Nonce, error)
+	ExpireNonceByID(id string) error
+}
+
+type TasksRepository interface {
+	CreateTasks(tasks ...models.Task) error
+	UpdateTasks(tasks ...models.Task) error
+	StartScheduledTasks(workerID string, limit int, lease time.Duration, realms ...string) ([]models.Task, error)
+	RenewTaskLeases(workerID string, lease time.Duration, ids ...primitive.ObjectID) error
+	RestartAbandonedTasks() ([]models.Task, error)
+	GetTaskByID(id primitive.ObjectID) (models.Task, error)
+	FindTasks(query models.TaskQuery) ([]models.Task, error)
+	SetTaskStatus(id primitive.ObjectID, current, status models.TaskStatus) (models.Task, error)
+	CountTasks(since time.Time) (models.TaskCounts, error)
+}
+
+/*
+AuditLogRepository is append-only
+*/
+type AuditLogRepository interface {
+	AddAuditLogEntries(entries ...models.AuditLogEntry) error
+	FindAuditLogEntries(query models.AuditLogQuery) ([]models.AuditLogEntry, error)
+}
+
+/*
+ConfigurationRepository stores configuration values encoded as a raw bson value, package level functions decode it into a concrete type.
+*/
+type ConfigurationRepository interface {
+	UpdateAppConfiguration(key string, value bson.RawValue, metadata map[string]any, upsert bool) error
+	GetAppConfiguration(key string) (models.AppConfiguration[bson.RawValue], error)
+}
+
+/*
+Storage is implemented by the MongoDB Client and by the in-memory storage in database/memory.
+All package level functions in this package use DefaultStorage, which is set by Connect.
+*/
+type Storage interface {
+	AccountsRepository
+	ClansRepository
+	SessionsRepository
+	RollupsRepository
+	SnapshotsRepository
+	UsersRepository
+	ConnectionsRepository
+	SubscriptionsRepository
+	RestrictionsRepository
+	ContentRepository
+	GlossaryRepository
+	NonceRepository
+	TasksRepository
+	ConfigurationRepository
+	AuditLogRepository
+}
+
+var _ Storage = &Client{}
+
+var DefaultStorage Storage
+
+func marshalRawValue(data any) (bson.RawValue, error) {
+	kind, value, err := bson.MarshalValue(data)
+	if err != nil {
+		return bson.RawValue{}, err
+	}
+	return bson.RawValue{Type: kind, Value: value}, nil
+}
+
+func unmarshalRawValue(raw bson.RawValue, target any) error {
+	if raw.Value == nil {
+		return nil
+	}
+	return raw.Unmarshal(target)
+}
@@ -13,11 +13,15 @@ import (
 var ErrSubscriptionNotFound = errors.New("subscription not found")
 
 func GetSubscriptionByID(id primitive.ObjectID) (models.UserSubscription, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetSubscriptionByID(id)
+}
+
+func (c *Client) GetSubscriptionByID(id primitive.ObjectID) (models.UserSubscription, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var subscription models.UserSubscription
-	err := DefaultClient.Collection(CollectionUserSubscriptions).FindOne(ctx, bson.M{"_id": id}).Decode(&subscription)
+	err := c.Collection(CollectionUserSubscriptions).FindOne(ctx, bson.M{"_id": id}).Decode(&subscription)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return subscription, ErrSubscriptionNotFound
@@ -45,12 +49,16 @@ func FindActiveSubscriptionsByUserID(userId string) ([]models.UserSubscription,
 }
 
 func FindSubscriptionsByUserID(userId string) ([]models.UserSubscription, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.FindSubscriptionsByUserID(userId)
+}
+
+func (c *Client) FindSubscriptionsByUserID(userId string) ([]models.UserSubscription, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var subscriptions []models.UserSubscription
 	opts := options.Find().SetSort(bson.M{"creationDate": -1})
-	cur, err := DefaultClient.Collection(CollectionUserSubscriptions).Find(ctx, bson.M{"userID": userId}, opts)
+	cur, err := c.Collection(CollectionUserSubscriptions).Find(ctx, bson.M{"userID": userId}, opts)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrSubscriptionNotFound
@@ -62,16 +70,20 @@ func FindSubscriptionsByUserID(userId string) ([]models.UserSubscription, error)
 }
 
 func FindSubscriptionsByReferenceIDs(referenceIDs ...string) ([]models.UserSubscription, error) {
+	return DefaultStorage.FindSubscriptionsByReferenceIDs(referenceIDs...)
+}
+
+func (c *Client) FindSubscriptionsByReferenceIDs(referenceIDs ...string) ([]models.UserSubscription, error) {
 	if len(referenceIDs) == 0 {
 		return nil, nil
 	}
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var subscriptions []models.UserSubscription
 	opts := options.Find().SetSort(bson.M{"creationDate": 1})
-	cur, err := DefaultClient.Collection(CollectionUserSubscriptions).Find(ctx, bson.M{"referenceID": bson.M{"$in": referenceIDs}}, opts)
+	cur, err := c.Collection(CollectionUserSubscriptions).Find(ctx, bson.M{"referenceID": bson.M{"$in": referenceIDs}}, opts)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrSubscriptionNotFound
@@ -99,12 +111,16 @@ func FindActiveSubscriptionsByReferenceIDs(referenceIDs ...string) ([]models.Use
 }
 
 func AddNewUserSubscription(userId string, payload models.UserSubscription) (models.UserSubscription, error) {
+	return DefaultStorage.AddNewUserSubscription(userId, payload)
+}
+
+func (c *Client) AddNewUserSubscription(userId string, payload models.UserSubscription) (models.UserSubscription, error) {
 	payload.ID = primitive.NilObjectID // Ensure ID is empty
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	result, err := DefaultClient.Collection(CollectionUserSubscriptions).InsertOne(ctx, payload)
+	result, err := c.Collection(CollectionUserSubscriptions).InsertOne(ctx, payload)
 	if err != nil {
 		return models.UserSubscription{}, err
 	}
@@ -119,10 +135,14 @@ func AddNewUserSubscription(userId string, payload models.UserSubscription) (mod
 }
 
 func UpdateUserSubscription(id primitive.ObjectID, payload models.SubscriptionUpdate) (models.UserSubscription, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.UpdateUserSubscription(id, payload)
+}
+
+func (c *Client) UpdateUserSubscription(id primitive.ObjectID, payload models.SubscriptionUpdate) (models.UserSubscription, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionUserSubscriptions).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": payload})
+	_, err := c.Collection(CollectionUserSubscriptions).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": payload})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return models.UserSubscription{}, ErrSubscriptionNotFound
@@ -130,5 +150,5 @@ func UpdateUserSubscription(id primitive.ObjectID, payload models.SubscriptionUp
 		return models.UserSubscription{}, err
 	}
 
-	return GetSubscriptionByID(id)
+	return c.GetSubscriptionByID(id)
 }
@@ -0,0 +1,309 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskStatusChanged = errors.New("task status changed")
+)
+
+func CreateTasks(tasks ...models.Task) error {
+	return DefaultStorage.CreateTasks(tasks...)
+}
+
+func (c *Client) CreateTasks(tasks ...models.Task) error {
+	var writes []mongo.WriteModel
+	for _, task := range tasks {
+		writes = append(writes, mongo.NewInsertOneModel().SetDocument(task))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionTasks).BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+UpdateTasks saves tasks by ID, tasks claimed by a worker are only saved while the claim is still held by the same worker.
+*/
+func UpdateTasks(tasks ...models.Task) error {
+	return DefaultStorage.UpdateTasks(tasks...)
+}
+
+func (c *Client) UpdateTasks(tasks ...models.Task) error {
+	var writes []mongo.WriteModel
+	for _, task := range tasks {
+		filter := bson.M{"_id": task.ID}
+		if task.WorkerID != "" {
+			filter["worker_id"] = task.WorkerID
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$set": task}))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	_, err := c.Collection(CollectionTasks).BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/*
+StartScheduledTasks claims up to limit tasks with status TaskStatusScheduled that are due, claimed tasks are set to TaskStatusInProgress with a lease held by workerID.
+Tasks are claimed by priority, oldest first. When realms are provided, only tasks for those realms are claimed.
+Each task is claimed with a separate find-and-modify, so concurrent workers never receive the same task.
+*/
+func StartScheduledTasks(workerID string, limit int, lease time.Duration, realms ...string) ([]models.Task, error) {
+	return DefaultStorage.StartScheduledTasks(workerID, limit, lease, realms...)
+}
+
+func (c *Client) StartScheduledTasks(workerID string, limit int, lease time.Duration, realms ...string) ([]models.Task, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "scheduled_after", Value: 1}}).SetReturnDocument(options.After)
+
+	var tasks []models.Task
+	for len(tasks) < limit {
+		now := time.Now()
+		filter := bson.M{"status": models.TaskStatusScheduled, "scheduled_after": bson.M{"$lte": now}}
+		if len(realms) > 0 {
+			filter["data.realm"] = bson.M{"$in": realms}
+		}
+		update := bson.M{"$set": bson.M{"status": models.TaskStatusInProgress, "worker_id": workerID, "lease_expires_at": now.Add(lease), "last_attempt": now}}
+
+		var task models.Task
+		err := c.Collection(CollectionTasks).FindOneAndUpdate(ctx, filter, update, opts).Decode(&task)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				break
+			}
+			return tasks, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+/*
+RenewTaskLeases extends leases on tasks that are still in progress and held by workerID
+*/
+func RenewTaskLeases(workerID string, lease time.Duration, ids ...primitive.ObjectID) error {
+	return DefaultStorage.RenewTaskLeases(workerID, lease, ids...)
+}
+
+func (c *Client) RenewTaskLeases(workerID string, lease time.Duration, ids ...primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "status": models.TaskStatusInProgress, "worker_id": workerID}
+	_, err := c.Collection(CollectionTasks).UpdateMany(ctx, filter, bson.M{"$set": bson.M{"lease_expires_at": time.Now().Add(lease)}})
+	return err
+}
+
+/*
+RestartAbandonedTasks reschedules all tasks with status TaskStatusInProgress that have an expired lease and returns them with status TaskStatusScheduled.
+Tasks started before leases were introduced are considered abandoned when their last attempt was more than an hour ago.
+*/
+func RestartAbandonedTasks() ([]models.Task, error) {
+	return DefaultStorage.RestartAbandonedTasks()
+}
+
+func (c *Client) RestartAbandonedTasks() ([]models.Task, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var tasks []models.Task
+	for {
+		now := time.Now()
+		filter := bson.M{"status": models.TaskStatusInProgress, "$or": []bson.M{
+			{"lease_expires_at": bson.M{"$lte": now}},
+			{"lease_expires_at": bson.M{"$exists": false}, "last_attempt": bson.M{"$lte": now.Add(-time.Hour)}},
+		}}
+		update := bson.M{"$set": bson.M{"status": models.TaskStatusScheduled, "worker_id": "", "lease_expires_at": time.Time{}}}
+
+		var task models.Task
+		err := c.Collection(CollectionTasks).FindOneAndUpdate(ctx, filter, update, opts).Decode(&task)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				break
+			}
+			return tasks, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func GetTaskByID(id primitive.ObjectID) (models.Task, error) {
+	return DefaultStorage.GetTaskByID(id)
+}
+
+func (c *Client) GetTaskByID(id primitive.ObjectID) (models.Task, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var task models.Task
+	err := c.Collection(CollectionTasks).FindOne(ctx, bson.M{"_id": id}).Decode(&task)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return task, ErrTaskNotFound
+		}
+		return task, err
+	}
+	return task, nil
+}
+
+/*
+FindTasks returns tasks matching the query sorted by creation time, newest first
+*/
+func FindTasks(query models.TaskQuery) ([]models.Task, error) {
+	return DefaultStorage.FindTasks(query)
+}
+
+func (c *Client) FindTasks(query models.TaskQuery) ([]models.Task, error) {
+	filter := bson.M{}
+	if query.Kind != "" {
+		filter["kind"] = query.Kind
+	}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+	if !query.From.IsZero() || !query.To.IsZero() {
+		createdAt := bson.M{}
+		if !query.From.IsZero() {
+			createdAt["$gte"] = query.From
+		}
+		if !query.To.IsZero() {
+			createdAt["$lte"] = query.To
+		}
+		filter["created_at"] = createdAt
+	}
+
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+	if query.Limit > 0 {
+		opts.SetLimit(int64(query.Limit))
+	}
+
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var tasks []models.Task
+	cur, err := c.Collection(CollectionTasks).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, cur.All(ctx, &tasks)
+}
+
+/*
+SetTaskStatus manually changes the status of a task and releases any worker lease on it, so results from a worker still processing the task are discarded.
+The task is only updated while its status is still current, ErrTaskStatusChanged is returned otherwise. Tasks set to TaskStatusScheduled are due immediately and get a fresh set of attempts.
+*/
+func SetTaskStatus(id primitive.ObjectID, current, status models.TaskStatus) (models.Task, error) {
+	return DefaultStorage.SetTaskStatus(id, current, status)
+}
+
+func (c *Client) SetTaskStatus(id primitive.ObjectID, current, status models.TaskStatus) (models.Task, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	update := bson.M{"status": status, "worker_id": "", "lease_expires_at": time.Time{}, "updated_at": time.Now()}
+	if status == models.TaskStatusScheduled {
+		update["scheduled_after"] = time.Now()
+		update["attempts"] = 0
+		update["failed_targets"] = nil
+	}
+
+	var task models.Task
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := c.Collection(CollectionTasks).FindOneAndUpdate(ctx, bson.M{"_id": id, "status": current}, bson.M{"$set": update}, opts).Decode(&task)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return task, err
+		}
+		count, err := c.Collection(CollectionTasks).CountDocuments(ctx, bson.M{"_id": id})
+		if err != nil {
+			return task, err
+		}
+		if count == 0 {
+			return task, ErrTaskNotFound
+		}
+		return task, ErrTaskStatusChanged
+	}
+	return task, nil
+}
+
+/*
+CountTasks returns the number of tasks per kind and status created after since
+*/
+func CountTasks(since time.Time) (models.TaskCounts, error) {
+	return DefaultStorage.CountTasks(since)
+}
+
+func (c *Client) CountTasks(since time.Time) (models.TaskCounts, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var pipeline mongo.Pipeline
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"created_at": bson.M{"$gte": since}}}})
+	pipeline = append(pipeline, bson.D{{Key: "$group", Value: bson.M{"_id": bson.M{"kind": "$kind", "status": "$status"}, "count": bson.M{"$sum": 1}}}})
+
+	cur, err := c.Collection(CollectionTasks).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		ID struct {
+			Kind   string            `bson:"kind"`
+			Status models.TaskStatus `bson:"status"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	err = cur.All(ctx, &results)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(models.TaskCounts)
+	for _, result := range results {
+		if counts[result.ID.Kind] == nil {
+			counts[result.ID.Kind] = make(map[models.TaskStatus]int)
+		}
+		counts[result.ID.Kind][result.ID.Status] = result.Count
+	}
+	return counts, nil
+}
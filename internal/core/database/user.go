@@ -6,6 +6,7 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
@@ -28,7 +29,11 @@ func GetOrCreateUserByID(id string) (models.CompleteUser, error) {
 }
 
 func GetUserByID(id string) (models.CompleteUser, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.GetUserByID(id)
+}
+
+func (c *Client) GetUserByID(id string) (models.CompleteUser, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var pipeline mongo.Pipeline
@@ -37,7 +42,7 @@ func GetUserByID(id string) (models.CompleteUser, error) {
 	pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: CollectionUserConnections}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "userID"}, {Key: "as", Value: "connections"}}}})
 	pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: CollectionUserSubscriptions}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "userID"}, {Key: "as", Value: "subscriptions"}}}})
 
-	cur, err := DefaultClient.Collection(CollectionUsers).Aggregate(ctx, pipeline)
+	cur, err := c.Collection(CollectionUsers).Aggregate(ctx, pipeline)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return models.CompleteUser{}, ErrUserNotFound
@@ -61,7 +66,11 @@ func GetUserByID(id string) (models.CompleteUser, error) {
 }
 
 func FindUserByConnection(connectionType models.ConnectionType, externalID string) (models.CompleteUser, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.FindUserByConnection(connectionType, externalID)
+}
+
+func (c *Client) FindUserByConnection(connectionType models.ConnectionType, externalID string) (models.CompleteUser, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	var pipeline mongo.Pipeline
@@ -73,7 +82,7 @@ func FindUserByConnection(connectionType models.ConnectionType, externalID strin
 	pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: CollectionUserConnections}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "userID"}, {Key: "as", Value: "connections"}}}})
 	pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: CollectionUserSubscriptions}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "userID"}, {Key: "as", Value: "subscriptions"}}}})
 
-	cur, err := DefaultClient.Collection(CollectionUsers).Aggregate(ctx, pipeline)
+	cur, err := c.Collection(CollectionUsers).Aggregate(ctx, pipeline)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return models.CompleteUser{}, ErrUserNotFound
@@ -97,12 +106,16 @@ func FindUserByConnection(connectionType models.ConnectionType, externalID strin
 }
 
 func CreateUser(id string) (models.User, error) {
-	ctx, cancel := DefaultClient.Ctx()
+	return DefaultStorage.CreateUser(id)
+}
+
+func (c *Client) CreateUser(id string) (models.User, error) {
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
 	user := models.NewUser(id)
 
-	_, err := DefaultClient.Collection(CollectionUsers).InsertOne(ctx, user)
+	_, err := c.Collection(CollectionUsers).InsertOne(ctx, user)
 	if err != nil {
 		return models.User{}, err
 	}
@@ -111,12 +124,16 @@ func CreateUser(id string) (models.User, error) {
 }
 
 func UpdateUser(id string, update models.User) (models.User, error) {
+	return DefaultStorage.UpdateUser(id, update)
+}
+
+func (c *Client) UpdateUser(id string, update models.User) (models.User, error) {
 	update.ID = id
 
-	ctx, cancel := DefaultClient.Ctx()
+	ctx, cancel := c.Ctx()
 	defer cancel()
 
-	_, err := DefaultClient.Collection(CollectionUsers).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	_, err := c.Collection(CollectionUsers).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return models.User{}, ErrUserNotFound
@@ -126,3 +143,58 @@ func UpdateUser(id string, update models.User) (models.User, error) {
 
 	return update, nil
 }
+
+/*
+UpdateUserSessionReset sets a custom session reset time for a user, a nil reset removes it
+*/
+func UpdateUserSessionReset(id string, reset *models.SessionReset) (models.User, error) {
+	return DefaultStorage.UpdateUserSessionReset(id, reset)
+}
+
+func (c *Client) UpdateUserSessionReset(id string, reset *models.SessionReset) (models.User, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"sessionReset": ""}}
+	if reset != nil {
+		update = bson.M{"$set": bson.M{"sessionReset": reset}}
+	}
+
+	var user models.User
+	err := c.Collection(CollectionUsers).FindOneAndUpdate(ctx, bson.M{"_id": id}, update, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+/*
+FindUsersWithSessionReset returns all users with a custom session reset time along with their connections
+*/
+func FindUsersWithSessionReset() ([]models.CompleteUser, error) {
+	return DefaultStorage.FindUsersWithSessionReset()
+}
+
+func (c *Client) FindUsersWithSessionReset() ([]models.CompleteUser, error) {
+	ctx, cancel := c.Ctx()
+	defer cancel()
+
+	var pipeline mongo.Pipeline
+	pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{{Key: "sessionReset", Value: bson.M{"$exists": true, "$ne": nil}}}}})
+	pipeline = append(pipeline, bson.D{{Key: "$lookup", Value: bson.D{{Key: "from", Value: CollectionUserConnections}, {Key: "localField", Value: "_id"}, {Key: "foreignField", Value: "userID"}, {Key: "as", Value: "connections"}}}})
+
+	cur, err := c.Collection(CollectionUsers).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []models.CompleteUser
+	if err := cur.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
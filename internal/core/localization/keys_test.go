@@ -0,0 +1,40 @@
+package localization_test
+
+import (
+	"testing"
+
+	"github.com/cufee/aftermath-core/dataprep"
+	"github.com/cufee/aftermath-core/dataprep/period"
+	"github.com/cufee/aftermath-core/dataprep/session"
+	"github.com/cufee/aftermath-core/internal/core/localization"
+	"github.com/cufee/aftermath-core/internal/logic/render/shared"
+	"github.com/cufee/aftermath-core/internal/logic/replay"
+)
+
+func TestMissingKeys(t *testing.T) {
+	var keys []string
+	for _, tag := range dataprep.AllTags {
+		keys = append(keys, "label_"+string(tag))
+	}
+	for _, highlight := range period.AllHighlights {
+		keys = append(keys, highlight.Label())
+	}
+	keys = append(keys, session.LabelOverviewRating, session.LabelOverviewUnrated, session.LabelRatingCalibrationBattlesLeft, session.LabelRatingTopPercent)
+
+	for _, tier := range shared.WN8Tiers {
+		keys = append(keys, tier.Label)
+	}
+
+	keys = append(keys, "label_victory", "label_defeat")
+	for _, battleType := range []interface{ String() string }{replay.BattleTypeUnknown, replay.BattleTypeRandom, replay.BattleTypeSupremacy} {
+		keys = append(keys, "label_"+battleType.String())
+	}
+
+	missing, err := localization.MissingKeys(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for locale, ids := range missing {
+		t.Errorf("%s bundle is missing %d keys: %v", locale, len(ids), ids)
+	}
+}
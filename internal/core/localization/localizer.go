@@ -12,6 +12,21 @@ import (
 
 type LocalePrinter func(string) string
 
+// Printers return message IDs with this prefix when there is no translation in any bundle
+const missingPrefix = "? "
+
+/*
+IsMissing returns true when a localized string is a message ID that was not found in any bundle
+*/
+func IsMissing(localized string) bool {
+	return strings.HasPrefix(localized, missingPrefix)
+}
+
+// Languages we ship message bundles for, English is the fallback and should always be first
+var SupportedLanguages = []language.Tag{language.English, language.Russian, language.Polish, language.German, language.Ukrainian}
+
+var matcher = language.NewMatcher(SupportedLanguages)
+
 //go:embed resources
 var resources embed.FS
 
@@ -46,8 +61,55 @@ func GetPrinter(locale language.Tag) LocalePrinter {
 		})
 		if err != nil {
 			log.Warn().Err(err).Msg("failed to localize string")
-			return "? " + s
+			return missingPrefix + s
 		}
 		return localized
 	}
 }
+
+/*
+ParseLocale picks a supported language from an explicit locale code, falling back to the Accept-Language header value and English
+*/
+func ParseLocale(locale, acceptLanguage string) language.Tag {
+	if locale != "" {
+		if tag, err := language.Parse(locale); err == nil {
+			if _, index, confidence := matcher.Match(tag); confidence > language.No {
+				return SupportedLanguages[index]
+			}
+		}
+	}
+	if acceptLanguage != "" {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+			if _, index, confidence := matcher.Match(tags...); confidence > language.No {
+				return SupportedLanguages[index]
+			}
+		}
+	}
+	return language.English
+}
+
+/*
+MissingKeys returns message IDs that have no translation in the bundle of each supported language
+*/
+func MissingKeys(ids ...string) (map[language.Tag][]string, error) {
+	missing := make(map[language.Tag][]string)
+	for _, locale := range SupportedLanguages {
+		data, err := resources.ReadFile("resources/" + locale.String() + ".json")
+		if err != nil {
+			return nil, err
+		}
+
+		var messages map[string]string
+		err = json.Unmarshal(data, &messages)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range ids {
+			if messages[id] == "" {
+				missing[locale] = append(missing[locale], id)
+			}
+		}
+	}
+	return missing, nil
+}
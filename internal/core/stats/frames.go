@@ -175,6 +175,26 @@ func (r *ReducedStatsFrame) WN8(vehicleAverages ...ReducedStatsFrame) int {
 	return r.wn8
 }
 
+/*
+WeightedWN8 calculates a WN8 rating weighted by the number of battles on each vehicle with known averages
+*/
+func WeightedWN8(vehicles map[int]ReducedVehicleStats, averages map[int]ReducedStatsFrame) (int, int) {
+	var weightedWN8Total, wn8BattlesTotal int
+	for id, vehicle := range vehicles {
+		if vehicle.ReducedStatsFrame == nil || vehicle.Battles < 1 {
+			continue
+		}
+		if data, ok := averages[id]; ok {
+			weightedWN8Total += vehicle.Battles * vehicle.WN8(data)
+			wn8BattlesTotal += vehicle.Battles
+		}
+	}
+	if wn8BattlesTotal < 1 {
+		return InvalidValueInt, 0
+	}
+	return weightedWN8Total / wn8BattlesTotal, wn8BattlesTotal
+}
+
 func (r *ReducedStatsFrame) Add(other ReducedStatsFrame) {
 	r.Battles += other.Battles
 	r.BattlesWon += other.BattlesWon
@@ -0,0 +1,38 @@
+package wargaming
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidAccessToken = errors.New("invalid access token")
+)
+
+/*
+AccessTokenValidator checks OpenID access tokens issued by Wargaming during account verification.
+ValidateAccessToken returns the ID of the account the token was issued for, tokens that do not grant access to accountID return ErrInvalidAccessToken.
+*/
+type AccessTokenValidator interface {
+	ValidateAccessToken(realm string, accountID int, accessToken string) (int, error)
+}
+
+var _ AccessTokenValidator = &FixtureTokenValidator{}
+
+/*
+FixtureTokenValidator validates tokens against an in-memory map of access token -> account id, it is meant for tests and offline runs
+*/
+type FixtureTokenValidator struct {
+	Err    error
+	Tokens map[string]int
+}
+
+func (v *FixtureTokenValidator) ValidateAccessToken(realm string, accountID int, accessToken string) (int, error) {
+	if v.Err != nil {
+		return 0, v.Err
+	}
+	id, ok := v.Tokens[accessToken]
+	if !ok {
+		return 0, ErrInvalidAccessToken
+	}
+	return id, nil
+}
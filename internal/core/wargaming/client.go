@@ -1,18 +1,83 @@
 package wargaming
 
 import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
 	"time"
 
-	"github.com/cufee/aftermath-core/internal/core/utils"
 	"github.com/cufee/am-wg-proxy-next/v2/remote"
+	"github.com/rs/zerolog/log"
 )
 
-var Clients struct {
-	Live  *remote.Client
-	Cache *remote.Client
+/*
+Providers holds the clients used for interactive requests and background refreshes
+*/
+type Providers struct {
+	Live  StatsProvider
+	Cache StatsProvider
 }
 
-func init() {
-	Clients.Live = remote.NewClient(utils.MustGetEnv("LIVE_WG_PROXY_URL"), time.Second*5)
-	Clients.Cache = remote.NewClient(utils.MustGetEnv("CACHE_WG_PROXY_URL"), time.Second*30)
+/*
+Clients are created by LoadClients on startup
+*/
+var Clients Providers
+
+/*
+Limiter is shared by the live and cache providers, live requests are served from the reserve when background refreshes use up the rest
+*/
+var Limiter *RealmLimiter
+
+const defaultRealmRateLimit = 20
+
+/*
+LoadClients creates the live and cache providers from env, an error is returned when either proxy url is not set
+*/
+func LoadClients() error {
+	live, err := providerFromEnv("LIVE_WG_PROXY_URL", time.Second*5)
+	if err != nil {
+		return err
+	}
+	cache, err := providerFromEnv("CACHE_WG_PROXY_URL", time.Second*30)
+	if err != nil {
+		return err
+	}
+
+	Limiter = limiterFromEnv("WG_PROXY_REALM_RATE_LIMIT")
+	Clients.Live = NewLimitedProvider(live, Limiter, PriorityInteractive)
+	Clients.Cache = NewLimitedProvider(cache, Limiter, PriorityBackground)
+	return nil
+}
+
+/*
+limiterFromEnv returns a limiter allowing the number of requests per second per realm set in env, a rate of 0 disables the limiter
+*/
+func limiterFromEnv(key string) *RealmLimiter {
+	rate := float64(defaultRealmRateLimit)
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			log.Warn().Str("key", key).Str("value", value).Msg("invalid realm rate limit, using the default")
+		} else {
+			rate = parsed
+		}
+	}
+	if rate == 0 {
+		return nil
+	}
+
+	burst := int(math.Ceil(rate))
+	return NewRealmLimiter(rate, burst, burst/4)
+}
+
+/*
+providerFromEnv returns a proxy client for the url in env
+*/
+func providerFromEnv(key string, timeout time.Duration) (StatsProvider, error) {
+	url := os.Getenv(key)
+	if url == "" {
+		return nil, fmt.Errorf("%w: %s is not set", ErrProviderNotConfigured, key)
+	}
+	return remote.NewClient(url, timeout), nil
 }
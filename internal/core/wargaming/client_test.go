@@ -0,0 +1,19 @@
+package wargaming
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLoadClients(t *testing.T) {
+	t.Setenv("LIVE_WG_PROXY_URL", "http://localhost")
+	t.Setenv("CACHE_WG_PROXY_URL", "")
+
+	err := LoadClients()
+	if !errors.Is(err, ErrProviderNotConfigured) {
+		t.Errorf("expected ErrProviderNotConfigured, got %v", err)
+	}
+	if Clients.Live != nil || Clients.Cache != nil {
+		t.Errorf("expected clients to be left unset, got %+v", Clients)
+	}
+}
@@ -0,0 +1,135 @@
+package wargaming
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cufee/am-wg-proxy-next/v2/types"
+	"github.com/cufee/am-wg-proxy-next/v2/utils"
+)
+
+var _ StatsProvider = &FixtureProvider{}
+
+/*
+FixtureProvider serves stats from in-memory maps, it is meant for tests and offline runs.
+Fields are ignored, all data is returned as stored. When Err is set, every call returns it.
+*/
+type FixtureProvider struct {
+	Err error
+
+	Accounts     map[int]types.ExtendedAccount
+	AccountClans map[int]types.ClanMember
+	Vehicles     map[int][]types.VehicleStatsFrame
+	Clans        map[int]types.ExtendedClan
+	Glossary     map[string]map[string]types.VehicleDetails // language -> vehicle id -> details
+}
+
+func NewFixtureProvider() *FixtureProvider {
+	return &FixtureProvider{
+		Accounts:     make(map[int]types.ExtendedAccount),
+		AccountClans: make(map[int]types.ClanMember),
+		Vehicles:     make(map[int][]types.VehicleStatsFrame),
+		Clans:        make(map[int]types.ExtendedClan),
+		Glossary:     make(map[string]map[string]types.VehicleDetails),
+	}
+}
+
+/*
+AddAccount adds an account with vehicles to the fixture, clan membership is optional and only saved when ClanID is set
+*/
+func (p *FixtureProvider) AddAccount(account types.ExtendedAccount, vehicles []types.VehicleStatsFrame, clan types.ClanMember) {
+	p.Accounts[account.ID] = account
+	p.Vehicles[account.ID] = vehicles
+	if clan.ClanID != 0 {
+		clan.AccountID = account.ID
+		p.AccountClans[account.ID] = clan
+	}
+}
+
+func (p *FixtureProvider) SearchAccounts(realm, query string, fields ...string) (types.Account, error) {
+	if p.Err != nil {
+		return types.Account{}, p.Err
+	}
+	for _, account := range p.Accounts {
+		if strings.EqualFold(account.Nickname, query) && strings.EqualFold(utils.RealmFromPlayerID(account.ID), realm) {
+			return account.Account, nil
+		}
+	}
+	return types.Account{}, ErrNotFound
+}
+
+func (p *FixtureProvider) BulkGetAccountsByID(ids []string, realm string, fields ...string) (map[string]types.ExtendedAccount, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	accounts := make(map[string]types.ExtendedAccount)
+	for _, account := range p.Accounts {
+		id := fmt.Sprint(account.ID)
+		if contains(ids, id) {
+			accounts[id] = account
+		}
+	}
+	return accounts, nil
+}
+
+func (p *FixtureProvider) BulkGetAccountsClans(ids []string, realm string, fields ...string) (map[string]types.ClanMember, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	clans := make(map[string]types.ClanMember)
+	for accountID, clan := range p.AccountClans {
+		id := fmt.Sprint(accountID)
+		if contains(ids, id) {
+			clans[id] = clan
+		}
+	}
+	return clans, nil
+}
+
+func (p *FixtureProvider) GetAccountVehicles(id int, fields ...string) ([]types.VehicleStatsFrame, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	return p.Vehicles[id], nil
+}
+
+func (p *FixtureProvider) GetClanByID(realm string, id int, fields ...string) (types.ExtendedClan, error) {
+	if p.Err != nil {
+		return types.ExtendedClan{}, p.Err
+	}
+	clan, ok := p.Clans[id]
+	if !ok {
+		return types.ExtendedClan{}, ErrNotFound
+	}
+	return clan, nil
+}
+
+func (p *FixtureProvider) BulkGetClansByID(ids []string, realm string, fields ...string) (map[string]types.ExtendedClan, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	clans := make(map[string]types.ExtendedClan)
+	for clanID, clan := range p.Clans {
+		id := fmt.Sprint(clanID)
+		if contains(ids, id) {
+			clans[id] = clan
+		}
+	}
+	return clans, nil
+}
+
+func (p *FixtureProvider) GetVehiclesGlossary(lang string, fields ...string) (map[string]types.VehicleDetails, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	return p.Glossary[lang], nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package wargaming
+
+import (
+	"github.com/cufee/am-wg-proxy-next/v2/types"
+	"github.com/cufee/am-wg-proxy-next/v2/utils"
+)
+
+var _ StatsProvider = &LimitedProvider{}
+
+/*
+LimitedProvider waits on a RealmLimiter before every request to the underlying provider. Requests that are not tied to a realm are not limited.
+*/
+type LimitedProvider struct {
+	provider StatsProvider
+	limiter  *RealmLimiter
+	priority Priority
+}
+
+func NewLimitedProvider(provider StatsProvider, limiter *RealmLimiter, priority Priority) StatsProvider {
+	if limiter == nil {
+		return provider
+	}
+	return &LimitedProvider{provider: provider, limiter: limiter, priority: priority}
+}
+
+func (p *LimitedProvider) SearchAccounts(realm, query string, fields ...string) (types.Account, error) {
+	p.limiter.Wait(realm, p.priority)
+	return p.provider.SearchAccounts(realm, query, fields...)
+}
+
+func (p *LimitedProvider) BulkGetAccountsByID(ids []string, realm string, fields ...string) (map[string]types.ExtendedAccount, error) {
+	p.limiter.Wait(realm, p.priority)
+	return p.provider.BulkGetAccountsByID(ids, realm, fields...)
+}
+
+func (p *LimitedProvider) BulkGetAccountsClans(ids []string, realm string, fields ...string) (map[string]types.ClanMember, error) {
+	p.limiter.Wait(realm, p.priority)
+	return p.provider.BulkGetAccountsClans(ids, realm, fields...)
+}
+
+func (p *LimitedProvider) GetAccountVehicles(id int, fields ...string) ([]types.VehicleStatsFrame, error) {
+	p.limiter.Wait(utils.RealmFromPlayerID(id), p.priority)
+	return p.provider.GetAccountVehicles(id, fields...)
+}
+
+func (p *LimitedProvider) GetClanByID(realm string, id int, fields ...string) (types.ExtendedClan, error) {
+	p.limiter.Wait(realm, p.priority)
+	return p.provider.GetClanByID(realm, id, fields...)
+}
+
+func (p *LimitedProvider) BulkGetClansByID(ids []string, realm string, fields ...string) (map[string]types.ExtendedClan, error) {
+	p.limiter.Wait(realm, p.priority)
+	return p.provider.BulkGetClansByID(ids, realm, fields...)
+}
+
+func (p *LimitedProvider) GetVehiclesGlossary(lang string, fields ...string) (map[string]types.VehicleDetails, error) {
+	return p.provider.GetVehiclesGlossary(lang, fields...)
+}
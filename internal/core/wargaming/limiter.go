@@ -0,0 +1,81 @@
+package wargaming
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Priority int
+
+const (
+	PriorityBackground Priority = iota
+	PriorityInteractive
+)
+
+/*
+RealmLimiter is a token bucket per realm shared by all providers talking to the same proxy.
+Background requests can not use the last reserve tokens of a bucket, this keeps capacity available for interactive requests while bulk refreshes are running.
+*/
+type RealmLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens per second
+	burst   float64
+	reserve float64
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewRealmLimiter(rate float64, burst, reserve int) *RealmLimiter {
+	return &RealmLimiter{
+		rate:    rate,
+		burst:   float64(max(burst, 1)),
+		reserve: float64(max(0, min(reserve, burst-1))),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+/*
+Wait blocks until a request to realm with a given priority is allowed
+*/
+func (l *RealmLimiter) Wait(realm string, priority Priority) {
+	for {
+		delay := l.reserve1(strings.ToUpper(realm), priority)
+		if delay <= 0 {
+			return
+		}
+		time.Sleep(delay)
+	}
+}
+
+/*
+reserve1 takes a token when one is available, otherwise it returns how long to wait before trying again
+*/
+func (l *RealmLimiter) reserve1(realm string, priority Priority) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := l.buckets[realm]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.buckets[realm] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.updated).Seconds()*l.rate)
+	bucket.updated = now
+
+	var floor float64
+	if priority < PriorityInteractive {
+		floor = l.reserve
+	}
+	if bucket.tokens-1 >= floor {
+		bucket.tokens--
+		return 0
+	}
+	return time.Duration((floor + 1 - bucket.tokens) / l.rate * float64(time.Second))
+}
@@ -0,0 +1,30 @@
+package wargaming
+
+import (
+	"testing"
+)
+
+func TestRealmLimiterReserve(t *testing.T) {
+	limiter := NewRealmLimiter(1, 4, 2)
+
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve1("EU", PriorityBackground); delay > 0 {
+			t.Fatalf("background request %d: expected a token, got delay %s", i, delay)
+		}
+	}
+	if delay := limiter.reserve1("EU", PriorityBackground); delay <= 0 {
+		t.Errorf("expected background requests to leave the reserve untouched")
+	}
+	for i := 0; i < 2; i++ {
+		if delay := limiter.reserve1("EU", PriorityInteractive); delay > 0 {
+			t.Fatalf("interactive request %d: expected a token from the reserve, got delay %s", i, delay)
+		}
+	}
+	if delay := limiter.reserve1("EU", PriorityInteractive); delay <= 0 {
+		t.Errorf("expected an empty bucket to delay interactive requests")
+	}
+
+	if delay := limiter.reserve1("NA", PriorityBackground); delay > 0 {
+		t.Errorf("expected realms to have separate buckets, got delay %s", delay)
+	}
+}
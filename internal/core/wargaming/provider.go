@@ -0,0 +1,31 @@
+package wargaming
+
+import (
+	"errors"
+
+	"github.com/cufee/am-wg-proxy-next/v2/remote"
+	"github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+var (
+	ErrProviderNotConfigured = errors.New("stats provider is not configured")
+	ErrNotFound              = errors.New("not found")
+)
+
+/*
+StatsProvider is a source of accounts, clans, vehicles and glossary data. The am-wg-proxy remote client is the default implementation.
+Last battle times are fetched through BulkGetAccountsByID with the last_battle_time field.
+*/
+type StatsProvider interface {
+	SearchAccounts(realm, query string, fields ...string) (types.Account, error)
+	BulkGetAccountsByID(ids []string, realm string, fields ...string) (map[string]types.ExtendedAccount, error)
+	BulkGetAccountsClans(ids []string, realm string, fields ...string) (map[string]types.ClanMember, error)
+	GetAccountVehicles(id int, fields ...string) ([]types.VehicleStatsFrame, error)
+
+	GetClanByID(realm string, id int, fields ...string) (types.ExtendedClan, error)
+	BulkGetClansByID(ids []string, realm string, fields ...string) (map[string]types.ExtendedClan, error)
+
+	GetVehiclesGlossary(lang string, fields ...string) (map[string]types.VehicleDetails, error)
+}
+
+var _ StatsProvider = &remote.Client{}
@@ -13,8 +13,13 @@ import (
 	wg "github.com/cufee/am-wg-proxy-next/v2/types"
 )
 
-func CacheAllNewClanMembers(realm string, clanId int) error {
-	clan, err := wargaming.Clients.Cache.GetClanByID(realm, clanId)
+func CacheAllNewClanMembers(client wargaming.StatsProvider, realm string, clanId int) error {
+	clan, err := client.GetClanByID(realm, clanId)
+	if err != nil {
+		return err
+	}
+
+	err = saveClans(realm, clan)
 	if err != nil {
 		return err
 	}
@@ -34,7 +39,7 @@ func CacheAllNewClanMembers(realm string, clanId int) error {
 		return nil
 	}
 
-	_, err = RefreshSessionsAndAccounts(models.SessionTypeDaily, nil, realm, newAccounts...)
+	_, err = RefreshSessionsAndAccounts(client, models.SessionTypeDaily, nil, realm, newAccounts...)
 	if err != nil {
 		return err
 	}
@@ -55,18 +60,18 @@ func UpdatePlayerAccountsFromWG(realm string, accounts ...wg.ExtendedAccount) er
 /*
 UpdateRealmAccountsCache updates all active accounts for a realm in the cache.
 */
-func UpdateRealmAccountsCache(realm string) error {
+func UpdateRealmAccountsCache(client wargaming.StatsProvider, realm string) error {
 	accountIDs, err := database.GetRealmAccountIDs(realm)
 	if err != nil {
 		return err
 	}
-	return UpdateAccountsCache(realm, accountIDs)
+	return UpdateAccountsCache(client, realm, accountIDs)
 }
 
 /*
 UpdateAccountCache updates active accounts in the cache.
 */
-func UpdateAccountsCache(realm string, accountIDs []int) error {
+func UpdateAccountsCache(client wargaming.StatsProvider, realm string, accountIDs []int) error {
 	var waitGroup sync.WaitGroup
 
 	batches := utils.BatchAccountIDs(accountIDs, 100)
@@ -84,7 +89,7 @@ func UpdateAccountsCache(realm string, accountIDs []int) error {
 				accountIDsString[i] = fmt.Sprintf("%d", accountID)
 			}
 
-			accounts, err := wargaming.Clients.Cache.BulkGetAccountsByID(accountIDsString, realm)
+			accounts, err := client.BulkGetAccountsByID(accountIDsString, realm)
 			accountsChan <- utils.DataWithError[map[string]wg.ExtendedAccount]{Data: accounts, Err: err}
 		}(batch)
 	}
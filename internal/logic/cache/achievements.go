@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
+)
+
+/*
+RecordAccountsAchievements saves a snapshot of current achievements for each account, returns IDs of accounts that were not found.
+Snapshots for a custom session reset are saved with its reference, referenceID is empty for realm-wide snapshots.
+*/
+func RecordAccountsAchievements(realm string, referenceID string, accountIDs ...int) ([]int, error) {
+	achievements, err := wotblitz.GetAccountsAchievements(realm, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var missing []int
+	var snapshots []models.AchievementsSnapshot
+	for _, id := range accountIDs {
+		data, ok := achievements[id]
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		snapshots = append(snapshots, models.AchievementsSnapshot{
+			AccountID:    id,
+			ReferenceID:  referenceID,
+			Achievements: data.Achievements,
+			MaxSeries:    data.MaxSeries,
+			CreatedAt:    now,
+		})
+	}
+
+	return missing, database.InsertAchievementsSnapshots(snapshots...)
+}
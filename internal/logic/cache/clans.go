@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	wg "github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+/*
+UpdateClansCache refreshes clan details and members for all clans, recording members who joined or left since the last update.
+*/
+func UpdateClansCache(client wargaming.StatsProvider, realm string, clanIDs ...int) error {
+	var clans []wg.ExtendedClan
+	for _, batch := range utils.BatchAccountIDs(clanIDs, 100) {
+		ids := make([]string, len(batch))
+		for i, id := range batch {
+			ids[i] = fmt.Sprintf("%d", id)
+		}
+
+		data, err := client.BulkGetClansByID(ids, realm)
+		if err != nil {
+			return err
+		}
+		for _, clan := range data {
+			if clan.ID == 0 {
+				continue
+			}
+			clans = append(clans, clan)
+		}
+	}
+
+	return saveClans(realm, clans...)
+}
+
+func saveClans(realm string, clans ...wg.ExtendedClan) error {
+	if len(clans) == 0 {
+		return nil
+	}
+
+	var clanIDs []int
+	for _, clan := range clans {
+		clanIDs = append(clanIDs, clan.ID)
+	}
+
+	existing, err := database.GetClans(clanIDs...)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var documents []models.Clan
+	var events []models.ClanMemberEvent
+	for _, clan := range clans {
+		document := clanToDatabaseClan(realm, clan)
+		document.LastUpdated = now
+		document.CreatedAt = now
+
+		// Joins and leaves can only be detected once we have a previous member list
+		if previous, ok := existing[clan.ID]; ok {
+			events = append(events, diffClanMembers(clan.ID, previous.Members, document.Members, now)...)
+		}
+		documents = append(documents, document)
+	}
+
+	err = database.UpdateClans(documents...)
+	if err != nil {
+		return err
+	}
+	return database.InsertClanMemberEvents(events...)
+}
+
+func diffClanMembers(clanID int, previous, current []int, timestamp time.Time) []models.ClanMemberEvent {
+	previousMembers := make(map[int]struct{}, len(previous))
+	for _, id := range previous {
+		previousMembers[id] = struct{}{}
+	}
+	currentMembers := make(map[int]struct{}, len(current))
+	for _, id := range current {
+		currentMembers[id] = struct{}{}
+	}
+
+	var events []models.ClanMemberEvent
+	for _, id := range current {
+		if _, ok := previousMembers[id]; !ok {
+			events = append(events, models.ClanMemberEvent{Type: models.ClanMemberEventJoined, ClanID: clanID, AccountID: id, CreatedAt: timestamp})
+		}
+	}
+	for _, id := range previous {
+		if _, ok := currentMembers[id]; !ok {
+			events = append(events, models.ClanMemberEvent{Type: models.ClanMemberEventLeft, ClanID: clanID, AccountID: id, CreatedAt: timestamp})
+		}
+	}
+	return events
+}
+
+func clanToDatabaseClan(realm string, clan wg.ExtendedClan) models.Clan {
+	members := clan.MembersIDS
+	if clan.IsClanDisbanded {
+		members = nil
+	}
+	if members == nil {
+		members = []int{}
+	}
+
+	return models.Clan{
+		ID:       clan.ID,
+		Tag:      clan.Tag,
+		Name:     clan.Name,
+		Realm:    strings.ToUpper(realm),
+		EmblemID: fmt.Sprint(clan.EmblemSetID),
+		Members:  members,
+	}
+}
@@ -3,11 +3,14 @@ package cache
 import (
 	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
 	"github.com/cufee/aftermath-core/internal/logic/external/wotinspector"
+	"golang.org/x/text/language"
 )
 
-func UpdateGlossaryCache() error {
-	vehiclesMap, err := wotinspector.GetCompleteVehicleGlossary()
+func UpdateGlossaryCache(client wargaming.StatsProvider) error {
+	vehiclesMap, err := wotinspector.GetCompleteVehicleGlossary(client)
 	if err != nil {
 		return err
 	}
@@ -19,3 +22,39 @@ func UpdateGlossaryCache() error {
 
 	return database.UpdateGlossary(vehicles)
 }
+
+func UpdateAchievementsGlossaryCache() error {
+	achievements := make(map[string]models.Achievement)
+	glossaryLocales := []language.Tag{language.English, language.Russian, language.Polish}
+	for _, locale := range glossaryLocales {
+		glossary, err := wotblitz.GetAchievementsGlossary("EU", locale.String())
+		if err != nil {
+			return err
+		}
+
+		for id, achievement := range glossary {
+			existingData, ok := achievements[id]
+			if !ok {
+				existingData = models.Achievement{
+					ID:             id,
+					Section:        achievement.Section,
+					ImageURL:       achievement.ImageBigURL,
+					Description:    achievement.Description,
+					LocalizedNames: make(map[string]string),
+				}
+				if existingData.ImageURL == "" {
+					existingData.ImageURL = achievement.ImageURL
+				}
+			}
+			existingData.LocalizedNames[locale.String()] = achievement.Name
+			achievements[id] = existingData
+		}
+	}
+
+	var documents []models.Achievement
+	for _, achievement := range achievements {
+		documents = append(documents, achievement)
+	}
+
+	return database.UpdateAchievementsGlossary(documents)
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
+	"github.com/cufee/aftermath-core/internal/logic/stats/rating"
+	"github.com/rs/zerolog/log"
+
+	wg "github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+/*
+RecordRatingSnapshots saves a rating snapshot for the current season for each account that played rating battles since the last snapshot.
+*/
+func RecordRatingSnapshots(client wargaming.StatsProvider, realm string, seasonID int, accountIDs ...int) (map[int]error, error) {
+	if len(accountIDs) > 100 {
+		return nil, fmt.Errorf("too many account IDs: %d", len(accountIDs))
+	}
+
+	ids := make([]string, len(accountIDs))
+	for i, id := range accountIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+
+	accounts, err := client.BulkGetAccountsByID(ids, realm)
+	if err != nil {
+		return nil, err
+	}
+
+	lastSnapshots, err := database.GetLastRatingSnapshots(seasonID, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var waitGroup sync.WaitGroup
+	snapshotsChan := make(chan utils.DataWithError[models.RatingSnapshot], len(accounts))
+	for _, account := range accounts {
+		if account.ID == 0 || account.Statistics.Rating.Battles == 0 {
+			continue
+		}
+		if last, ok := lastSnapshots[account.ID]; ok && last.Stats.Battles == account.Statistics.Rating.Battles {
+			log.Debug().Msgf("%d played 0 rating battles since last snapshot, skipping update", account.ID)
+			continue
+		}
+
+		waitGroup.Add(1)
+		go func(account wg.ExtendedAccount) {
+			defer waitGroup.Done()
+
+			position, err := wotblitz.GetPlayerRatingPosition(account.ID, 0)
+			if err != nil {
+				snapshotsChan <- utils.DataWithError[models.RatingSnapshot]{Data: models.RatingSnapshot{AccountID: account.ID}, Err: err}
+				return
+			}
+			snapshotsChan <- utils.DataWithError[models.RatingSnapshot]{Data: rating.NewSnapshot(realm, seasonID, account, position)}
+		}(account)
+	}
+
+	waitGroup.Wait()
+	close(snapshotsChan)
+
+	updateErrors := make(map[int]error)
+	var snapshots []models.RatingSnapshot
+	for snapshot := range snapshotsChan {
+		if snapshot.Err != nil {
+			updateErrors[snapshot.Data.AccountID] = snapshot.Err
+			continue
+		}
+		snapshots = append(snapshots, snapshot.Data)
+	}
+
+	return updateErrors, database.InsertRatingSnapshots(snapshots...)
+}
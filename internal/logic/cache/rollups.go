@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+)
+
+/*
+RollupRetentionConfigurationKey holds a models.RollupRetentionConfiguration in the configuration collection, models.DefaultRollupRetention is used when it is not set
+*/
+const RollupRetentionConfigurationKey = "sessionRollupRetention"
+
+/*
+CompactSessionRollups copies the latest daily snapshot of each account into the weekly and monthly rollups for the current period.
+The first snapshot seen in a period is kept, later runs only extend the expiration when the retention of an account grows.
+*/
+func CompactSessionRollups(accountIDs ...int) error {
+	snapshots, err := database.GetLatestSessionSnapshots(models.SessionTypeDaily, accountIDs...)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	retention, err := rollupRetention(accountIDs...)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var rollups []models.SessionRollup
+	for accountID, snapshot := range snapshots {
+		for _, period := range models.AllRollupPeriods {
+			keep := retention[accountID].For(period)
+			if keep <= 0 {
+				continue
+			}
+			start := period.Start(now)
+			rollups = append(rollups, models.SessionRollup{
+				Period:      period,
+				PeriodStart: start,
+				RecordedAt:  snapshot.CreatedAt,
+				ExpiresAt:   start.Add(keep),
+				Session:     snapshot.Session,
+			})
+		}
+	}
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	return database.UpsertSessionRollups(rollups...)
+}
+
+/*
+rollupRetention finds the retention for each account based on active subscriptions referencing the account or any user it is linked to
+*/
+func rollupRetention(accountIDs ...int) (map[int]models.RollupRetention, error) {
+	config := models.DefaultRollupRetention
+	stored, err := database.GetAppConfiguration[models.RollupRetentionConfiguration](RollupRetentionConfigurationKey)
+	if err == nil {
+		config = stored.Value
+	} else if !errors.Is(err, database.ErrConfigurationNotFound) {
+		return nil, err
+	}
+
+	var referenceIDs []string
+	for _, id := range accountIDs {
+		referenceIDs = append(referenceIDs, fmt.Sprint(id))
+	}
+
+	connections, err := database.FindConnectionsByReferenceIDs(models.ConnectionTypeWargaming, referenceIDs...)
+	if err != nil {
+		return nil, err
+	}
+	accountUsers := make(map[string][]string)
+	for _, connection := range connections {
+		accountUsers[connection.ExternalID] = append(accountUsers[connection.ExternalID], connection.UserID)
+		referenceIDs = append(referenceIDs, connection.UserID)
+	}
+
+	subscriptions, err := database.FindActiveSubscriptionsByReferenceIDs(referenceIDs...)
+	if err != nil {
+		return nil, err
+	}
+	subscriptionTypes := make(map[string][]models.SubscriptionType)
+	for _, subscription := range subscriptions {
+		subscriptionTypes[subscription.ReferenceID] = append(subscriptionTypes[subscription.ReferenceID], subscription.Type)
+	}
+
+	retention := make(map[int]models.RollupRetention)
+	for _, id := range accountIDs {
+		reference := fmt.Sprint(id)
+		types := subscriptionTypes[reference]
+		for _, user := range accountUsers[reference] {
+			types = append(types, subscriptionTypes[user]...)
+		}
+		retention[id] = config.Retention(types...)
+	}
+	return retention, nil
+}
@@ -11,8 +11,8 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func RefreshSessionsAndAccounts(sessionType models.SessionType, referenceId *string, realm string, accountIDs ...int) (map[int]error, error) {
-	sessions, err := stats.GetCompleteStatsWithClient(wargaming.Clients.Cache, realm, accountIDs...)
+func RefreshSessionsAndAccounts(client wargaming.StatsProvider, sessionType models.SessionType, referenceId *string, realm string, accountIDs ...int) (map[int]error, error) {
+	sessions, err := stats.GetCompleteStatsWithClient(client, realm, accountIDs...)
 	if err != nil {
 		return nil, err
 	}
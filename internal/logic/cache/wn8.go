@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	core "github.com/cufee/aftermath-core/internal/core/stats"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/stats"
+)
+
+/*
+UpdateAccountsWN8 calculates a weighted career WN8 for all accounts and saves it on the account documents.
+*/
+func UpdateAccountsWN8(client wargaming.StatsProvider, realm string, accountIDs ...int) (map[int]error, error) {
+	allStats, err := stats.GetCompleteStatsWithClient(client, realm, accountIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	var vehicleIDs []int
+	for _, data := range allStats {
+		if data.Err != nil {
+			continue
+		}
+		for id := range data.Data.Session.Vehicles {
+			vehicleIDs = append(vehicleIDs, id)
+		}
+	}
+
+	averages, err := database.GetVehicleAverages(vehicleIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updateErrors := make(map[int]error)
+	values := make(map[int]models.AccountWN8)
+	for accountId, data := range allStats {
+		if data.Err != nil {
+			updateErrors[accountId] = data.Err
+			continue
+		}
+
+		wn8, battles := core.WeightedWN8(data.Data.Session.Vehicles, averages)
+		if wn8 == core.InvalidValueInt {
+			continue
+		}
+		values[data.Data.Account.ID] = models.AccountWN8{
+			Value:          wn8,
+			Battles:        battles,
+			LastBattleTime: data.Data.Account.LastBattleTime,
+			CreatedAt:      now,
+		}
+	}
+
+	return updateErrors, database.UpdateAccountsWN8(values)
+}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 
 	"github.com/cufee/aftermath-core/errors"
 	"github.com/cufee/aftermath-core/internal/core/cloudinary"
@@ -23,7 +24,7 @@ func UploadUserImage(userID, remoteImage string) (string, error) {
 		return "", err
 	}
 
-	link, err := cloudinary.DefaultClient.UploadWithModeration(userID, encodedImage)
+	link, err := cloudinary.DefaultClient().UploadWithModeration(userID, encodedImage)
 	if err != nil {
 		return "", err
 	}
@@ -64,6 +65,24 @@ func LoadRemoteImage(remoteImage string) (image.Image, string, error) {
 	return img, format, nil
 }
 
+var remoteImageCache sync.Map
+
+/*
+LoadCachedRemoteImage loads an image once and keeps it in memory, it is meant for a small set of static assets like league icons
+*/
+func LoadCachedRemoteImage(remoteImage string) (image.Image, error) {
+	if cached, ok := remoteImageCache.Load(remoteImage); ok {
+		return cached.(image.Image), nil
+	}
+
+	img, _, err := LoadRemoteImage(remoteImage)
+	if err != nil {
+		return nil, err
+	}
+	remoteImageCache.Store(remoteImage, img)
+	return img, nil
+}
+
 func EncodeRemoteImage(remoteImage string) (string, error) {
 	img, format, err := LoadRemoteImage(remoteImage)
 	if err != nil {
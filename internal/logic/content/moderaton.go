@@ -11,7 +11,7 @@ func PickRandomBackgroundImages(number int) ([]string, error) {
 		return nil, nil
 	}
 
-	images, err := cloudinary.DefaultClient.GetFolderImages("Aftermath/manual-uploads", 10)
+	images, err := cloudinary.DefaultClient().GetFolderImages("Aftermath/manual-uploads", 10)
 	if err != nil {
 		return nil, err
 	}
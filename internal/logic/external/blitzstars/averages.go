@@ -33,7 +33,7 @@ type VehicleAverages struct {
 }
 
 func GetTankAverages() (map[int]stats.ReducedStatsFrame, error) {
-	res, err := insecureClient.Get(starsStatsApiURL + "/tankaverages.json")
+	res, err := insecureClient.Get(starsStatsApiURL() + "/tankaverages.json")
 	if err != nil {
 		return nil, err
 	}
@@ -3,11 +3,13 @@ package blitzstars
 import (
 	"crypto/tls"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/utils"
 )
 
-var starsStatsApiURL = utils.MustGetEnv("BLITZ_STARS_API_URL")
+// Read on first use, packages importing blitzstars for history fallbacks do not need the API configured
+var starsStatsApiURL = sync.OnceValue(func() string { return utils.MustGetEnv("BLITZ_STARS_API_URL") })
 
 var insecureClient = &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
@@ -20,7 +20,7 @@ type ExtendedStatsFrame struct {
 }
 
 func GetPlayerStats(accountId int) (*TopPlayersResponse, error) {
-	res, err := insecureClient.Get(fmt.Sprintf("%s/top/player/%d", starsStatsApiURL, accountId))
+	res, err := insecureClient.Get(fmt.Sprintf("%s/top/player/%d", starsStatsApiURL(), accountId))
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +46,7 @@ type TankHistoryEntry struct {
 }
 
 func GetPlayerTankHistories(accountId int) (map[int][]TankHistoryEntry, error) {
-	res, err := insecureClient.Get(fmt.Sprintf("%s/tankhistories/for/%d", starsStatsApiURL, accountId))
+	res, err := insecureClient.Get(fmt.Sprintf("%s/tankhistories/for/%d", starsStatsApiURL(), accountId))
 	if err != nil {
 		return nil, err
 	}
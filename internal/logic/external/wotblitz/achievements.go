@@ -0,0 +1,57 @@
+package wotblitz
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type Achievement struct {
+	ID          string `json:"achievement_id"`
+	Name        string `json:"name"`
+	Section     string `json:"section"`
+	Condition   string `json:"condition"`
+	Description string `json:"description"`
+	ImageURL    string `json:"image"`
+	ImageBigURL string `json:"image_big"`
+	Order       int    `json:"order"`
+}
+
+type AccountAchievements struct {
+	Achievements map[string]int `json:"achievements"`
+	MaxSeries    map[string]int `json:"max_series"`
+}
+
+func GetAchievementsGlossary(realm string, lang string) (map[string]Achievement, error) {
+	return getFromWargaming[map[string]Achievement](realm, "/encyclopedia/achievements/", url.Values{"language": []string{lang}})
+}
+
+/*
+GetAccountsAchievements returns achievements for up to 100 accounts, accounts that are private or do not exist are omitted
+*/
+func GetAccountsAchievements(realm string, accountIDs ...int) (map[int]AccountAchievements, error) {
+	if len(accountIDs) == 0 {
+		return make(map[int]AccountAchievements), nil
+	}
+	if len(accountIDs) > 100 {
+		return nil, fmt.Errorf("too many account IDs: %d", len(accountIDs))
+	}
+
+	ids := make([]string, len(accountIDs))
+	for i, id := range accountIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+
+	data, err := getFromWargaming[map[string]*AccountAchievements](realm, "/account/achievements/", url.Values{"account_id": []string{strings.Join(ids, ",")}})
+	if err != nil {
+		return nil, err
+	}
+
+	achievements := make(map[int]AccountAchievements, len(data))
+	for _, id := range accountIDs {
+		if entry, ok := data[fmt.Sprint(id)]; ok && entry != nil {
+			achievements[id] = *entry
+		}
+	}
+	return achievements, nil
+}
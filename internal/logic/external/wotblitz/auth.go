@@ -0,0 +1,42 @@
+package wotblitz
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+)
+
+var _ wargaming.AccessTokenValidator = &TokenValidator{}
+
+/*
+TokenValidator validates access tokens against the official Wargaming account info endpoint.
+Private account data is only returned when the token was issued for the requested account.
+*/
+type TokenValidator struct{}
+
+type privateAccountInfo struct {
+	AccountID int            `json:"account_id"`
+	Private   map[string]any `json:"private"`
+}
+
+func (TokenValidator) ValidateAccessToken(realm string, accountID int, accessToken string) (int, error) {
+	query := url.Values{
+		"account_id":   []string{fmt.Sprint(accountID)},
+		"access_token": []string{accessToken},
+		"fields":       []string{"account_id,private.ban_time"},
+	}
+	data, err := getFromWargaming[map[string]*privateAccountInfo](realm, "/account/info/", query)
+	if err != nil {
+		if err.Error() == "invalid_access_token" {
+			return 0, wargaming.ErrInvalidAccessToken
+		}
+		return 0, err
+	}
+
+	info, ok := data[fmt.Sprint(accountID)]
+	if !ok || info == nil || info.Private == nil {
+		return 0, wargaming.ErrInvalidAccessToken
+	}
+	return info.AccountID, nil
+}
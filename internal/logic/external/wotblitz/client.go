@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/utils"
 )
 
-var apiBaseUrl = utils.MustGetEnv("WOT_BLITZ_PUBLIC_API_URL_FMT")
+// Read on first use, same as the official API settings in wargaming.go
+var apiBaseUrl = sync.OnceValue(func() string { return utils.MustGetEnv("WOT_BLITZ_PUBLIC_API_URL_FMT") })
 
-var client = http.DefaultClient
+var client = &http.Client{Timeout: 10 * time.Second}
 
 func realmToSubdomain(realm string) string {
 	switch strings.ToUpper(realm) {
@@ -22,5 +25,5 @@ func realmToSubdomain(realm string) string {
 }
 
 func apiUrl(realm string, endpoint string) string {
-	return fmt.Sprintf(apiBaseUrl, realmToSubdomain(realm)) + endpoint
+	return fmt.Sprintf(apiBaseUrl(), realmToSubdomain(realm)) + endpoint
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/cufee/am-wg-proxy-next/v2/utils"
 )
@@ -20,6 +21,32 @@ type RatingSeason struct {
 	TotalPlayers int `json:"count"`
 }
 
+func (s RatingSeason) Start() time.Time {
+	return parseSeasonTime(s.StartAt)
+}
+
+func (s RatingSeason) Finish() time.Time {
+	return parseSeasonTime(s.FinishAt)
+}
+
+func (s RatingSeason) League(index int) (League, bool) {
+	for _, league := range s.Leagues {
+		if league.Index == index {
+			return league, true
+		}
+	}
+	return League{}, false
+}
+
+func parseSeasonTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 type League struct {
 	Title      string  `json:"title"`
 	SmallIcon  string  `json:"small_icon"`
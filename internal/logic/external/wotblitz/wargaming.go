@@ -0,0 +1,65 @@
+package wotblitz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/cufee/aftermath-core/internal/core/utils"
+	wg "github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+// Read on first use, packages importing wotblitz for types or fixtures do not need the official API configured
+var wargamingApiUrlFmt = sync.OnceValue(func() string { return utils.MustGetEnv("WOT_BLITZ_WG_API_URL_FMT") })
+var wargamingAppID = sync.OnceValue(func() string { return utils.MustGetEnv("AUTH_WARGAMING_APP_ID") })
+
+type wargamingResponse[T any] struct {
+	wg.WgResponse
+	Data T `json:"data"`
+}
+
+func realmToDomain(realm string) string {
+	switch strings.ToUpper(realm) {
+	case "NA":
+		return "com"
+	case "AS":
+		return "asia"
+	default:
+		return strings.ToLower(realm)
+	}
+}
+
+/*
+getFromWargaming sends a GET request to the official Wargaming API and decodes the data field of a response into T
+*/
+func getFromWargaming[T any](realm string, endpoint string, query url.Values) (T, error) {
+	var data wargamingResponse[T]
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("application_id", wargamingAppID())
+
+	res, err := client.Get(fmt.Sprintf(wargamingApiUrlFmt(), realmToDomain(realm)) + endpoint + "?" + query.Encode())
+	if err != nil {
+		return data.Data, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return data.Data, fmt.Errorf("bad status code: %d", res.StatusCode)
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&data)
+	if err != nil {
+		return data.Data, err
+	}
+	if data.Status != "ok" {
+		return data.Data, errors.New(strings.ToLower(data.Error.Message))
+	}
+
+	return data.Data, nil
+}
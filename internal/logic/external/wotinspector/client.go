@@ -3,11 +3,13 @@ package wotinspector
 import (
 	"crypto/tls"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/utils"
 )
 
-var replayUploadUrl = utils.MustGetEnv("WOT_INSPECTOR_REPLAYS_URL")
+// Read on first use, packages importing wotinspector do not need it configured until a request is made
+var replayUploadUrl = sync.OnceValue(func() string { return utils.MustGetEnv("WOT_INSPECTOR_REPLAYS_URL") })
 
 var insecureClient = &http.Client{Timeout: 10 * time.Second, Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/utils"
@@ -23,17 +24,13 @@ type InspectorVehicle struct {
 	Premium int    `json:"premium"`
 }
 
-var inspectorVehiclesURL string
-
-func init() {
-	inspectorVehiclesURL = utils.MustGetEnv("WOT_INSPECTOR_TANK_DB_URL")
-}
+var inspectorVehiclesURL = sync.OnceValue(func() string { return utils.MustGetEnv("WOT_INSPECTOR_TANK_DB_URL") })
 
 func GetInspectorVehicles() (map[int]InspectorVehicle, error) {
 	re := regexp.MustCompile(`(\d{1,9}):`)
 	tanks := make(map[int]InspectorVehicle)
 
-	res, err := insecureClient.Get(inspectorVehiclesURL)
+	res, err := insecureClient.Get(inspectorVehiclesURL())
 	if err != nil || res == nil || res.StatusCode != http.StatusOK {
 		return tanks, fmt.Errorf("status code: %+v. error: %s", res, err)
 	}
@@ -53,11 +50,11 @@ func GetInspectorVehicles() (map[int]InspectorVehicle, error) {
 	return tanks, json.Unmarshal([]byte(tanksString), &tanks)
 }
 
-func GetCompleteVehicleGlossary() (map[int]models.Vehicle, error) {
+func GetCompleteVehicleGlossary(client wargaming.StatsProvider) (map[int]models.Vehicle, error) {
 	vehicles := make(map[int]models.Vehicle)
 	glossaryLocales := []language.Tag{language.English, language.Russian, language.Polish}
 	for _, locale := range glossaryLocales {
-		glossary, err := wargaming.Clients.Cache.GetVehiclesGlossary(locale.String())
+		glossary, err := client.GetVehiclesGlossary(locale.String())
 		if err != nil {
 			return nil, err
 		}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"embed"
 	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"path/filepath"
 	"strings"
 
@@ -0,0 +1,382 @@
+package render_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/dataprep"
+	periodprep "github.com/cufee/aftermath-core/dataprep/period"
+	replayprep "github.com/cufee/aftermath-core/dataprep/replay"
+	sessionprep "github.com/cufee/aftermath-core/dataprep/session"
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/localization"
+	core "github.com/cufee/aftermath-core/internal/core/stats"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/render"
+	"github.com/cufee/aftermath-core/internal/logic/render/assets"
+	periodrender "github.com/cufee/aftermath-core/internal/logic/render/period"
+	replayrender "github.com/cufee/aftermath-core/internal/logic/render/replay"
+	sessionrender "github.com/cufee/aftermath-core/internal/logic/render/session"
+	parse "github.com/cufee/aftermath-core/internal/logic/replay"
+	"github.com/cufee/aftermath-core/internal/logic/stats"
+	"github.com/cufee/aftermath-core/internal/logic/stats/period"
+	"github.com/cufee/aftermath-core/internal/logic/stats/sessions"
+	wg "github.com/cufee/am-wg-proxy-next/v2/types"
+	"golang.org/x/text/language"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate golden images in testdata/golden")
+
+const (
+	goldenPixelThreshold = 24    // max channel difference before a pixel is counted as changed
+	goldenTolerance      = 0.002 // share of changed pixels allowed before the test fails
+)
+
+const goldenAccountID = 1013072123
+
+// Shared with the live replay pipeline test in the repository root
+var goldenReplayPath = filepath.Join("..", "..", "..", "render_replay_test_0.wotbreplay")
+
+func TestGoldenSessionRender(t *testing.T) {
+	provider := setupGoldenFixtures(t)
+
+	// Save a snapshot from before the session, then move the live account forward
+	previous := goldenAccount(1000, 0)
+	provider.AddAccount(previous.account, previous.vehicles, previous.clan)
+	complete, err := stats.GetCompleteStatsWithClient(provider, "eu", goldenAccountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.InsertSession(models.SessionTypeDaily, nil, complete[goldenAccountID].Data.Session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	current := goldenAccount(1000, 12)
+	provider.AddAccount(current.account, current.vehicles, current.clan)
+
+	sessionData, err := sessions.GetCurrentPlayerSession(provider, goldenAccountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vehicleIDs []int
+	for id := range sessionData.Diff.Vehicles {
+		vehicleIDs = append(vehicleIDs, id)
+	}
+	averages, err := database.GetVehicleAverages(vehicleIDs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	glossary, err := database.GetGlossaryVehicles(vehicleIDs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unratedVehicles, ratingVehicles := stats.SortAndSplitVehicles(sessionData.Diff.Vehicles, averages, stats.SortOptions{By: stats.SortByLastBattle, Limit: 5}, stats.SortOptions{By: stats.SortByLastBattle, Limit: 3})
+	cards, err := sessionprep.SnapshotToSession(sessionprep.ExportInput{
+		SessionStats:           sessionData.Diff,
+		CareerStats:            sessionData.Selected,
+		SessionRatingVehicles:  ratingVehicles,
+		SessionUnratedVehicles: unratedVehicles,
+
+		VehicleGlossary:       glossary,
+		GlobalVehicleAverages: averages,
+	}, sessionprep.ExportOptions{
+		Blocks:                sessionprep.DefaultSessionBlocks,
+		Locale:                language.English,
+		LocalePrinter:         localization.GetPrinter(language.English),
+		IncludeRatingVehicles: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := sessionrender.RenderStatsImage(sessionrender.PlayerData{
+		Subscriptions: []models.UserSubscription{{Type: models.SubscriptionTypePro}},
+		Clan:          sessionData.Account.Clan,
+		Account:       sessionData.Account.Account,
+		Session:       sessionData,
+		Cards:         cards,
+	}, sessionrender.RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGoldenImage(t, "session", withGoldenBackground(img))
+}
+
+func TestGoldenPeriodRender(t *testing.T) {
+	provider := setupGoldenFixtures(t)
+
+	fixture := goldenAccount(1000, 12)
+	provider.AddAccount(fixture.account, fixture.vehicles, fixture.clan)
+
+	periodStats, err := period.GetPlayerStats(provider, goldenAccountID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vehicleIDs []int
+	for id := range periodStats.Vehicles {
+		vehicleIDs = append(vehicleIDs, id)
+	}
+	glossary, err := database.GetGlossaryVehicles(vehicleIDs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := periodprep.SnapshotToSession(periodprep.ExportInput{
+		Stats:           periodStats,
+		VehicleGlossary: glossary,
+	}, periodprep.ExportOptions{
+		Locale:        language.English,
+		LocalePrinter: localization.GetPrinter(language.English),
+
+		Blocks:     periodprep.DefaultBlocks,
+		Highlights: periodprep.DefaultHighlights,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := periodrender.RenderImage(periodrender.PlayerData{
+		Stats: periodStats,
+		Cards: cards,
+	}, periodrender.RenderOptions{Locale: language.English})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGoldenImage(t, "period", withGoldenBackground(img))
+}
+
+func TestGoldenReplayRender(t *testing.T) {
+	setupGoldenFixtures(t)
+
+	file, err := os.ReadFile(goldenReplayPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unpacked, err := parse.Unpack(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayData := parse.Prettify(unpacked.BattleResult, unpacked.Meta)
+
+	var vehicleIDs []int
+	for _, player := range append(replayData.Teams.Allies, replayData.Teams.Enemies...) {
+		vehicleIDs = append(vehicleIDs, player.VehicleID)
+	}
+	averages, err := database.GetVehicleAverages(vehicleIDs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := replayprep.ReplayToCards(replayprep.ExportInput{
+		GlobalVehicleAverages: averages,
+		Replay:                replayData,
+	}, replayprep.ExportOptions{
+		Locale:        language.English,
+		LocalePrinter: localization.GetPrinter(language.English),
+		Blocks:        []dataprep.Tag{dataprep.TagWN8, dataprep.TagDamageDealt, dataprep.TagDamageAssistedCombined, dataprep.TagFrags},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := replayrender.RenderReplayImage(replayrender.ReplayData{Cards: cards, Replay: replayData}, replayrender.RenderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGoldenImage(t, "replay", withGoldenBackground(img))
+}
+
+type goldenFixture struct {
+	account  wg.ExtendedAccount
+	vehicles []wg.VehicleStatsFrame
+	clan     wg.ClanMember
+}
+
+var goldenVehicles = []models.Vehicle{
+	{ID: 1, Tier: 10, Nation: "ussr", Class: models.VehicleClassMediumTank, Type: models.VehicleTypeRegular, LocalizedNames: map[string]string{"en": "T-62A"}},
+	{ID: 17, Tier: 10, Nation: "germany", Class: models.VehicleClassHeavyTank, Type: models.VehicleTypeRegular, LocalizedNames: map[string]string{"en": "E 100"}},
+	{ID: 33, Tier: 8, Nation: "usa", Class: models.VehicleClassMediumTank, Type: models.VehicleTypePremium, LocalizedNames: map[string]string{"en": "T26E4 SuperPershing"}},
+}
+
+/*
+goldenAccount returns the fixture account after playing battles on top of a fixed career, every vehicle gets an equal share of the new battles
+*/
+func goldenAccount(careerBattles, sessionBattles int) goldenFixture {
+	frame := func(battles int) wg.StatsFrame {
+		return wg.StatsFrame{
+			Battles:         battles,
+			Wins:            battles * 58 / 100,
+			Losses:          battles * 40 / 100,
+			SurvivedBattles: battles * 45 / 100,
+			DamageDealt:     battles * 2150,
+			DamageReceived:  battles * 1400,
+			Frags:           battles * 12 / 10,
+			Spotted:         battles * 11 / 10,
+			Shots:           battles * 9,
+			Hits:            battles * 7,
+			Xp:              battles * 950,
+			MaxFrags:        6,
+
+			DroppedCapturePoints: battles * 9 / 10,
+			MaxXp:                2400,
+		}
+	}
+
+	lastBattleTime := 1700000000 + sessionBattles*600
+
+	var fixture goldenFixture
+	fixture.account = wg.ExtendedAccount{Account: wg.Account{ID: goldenAccountID, Nickname: "GoldenFixture"}, CreatedAt: 1500000000, LastBattleTime: lastBattleTime}
+	fixture.account.Statistics.All = frame(careerBattles + sessionBattles)
+	fixture.clan = wg.ClanMember{ClanID: 10, Clan: wg.Clan{ID: 10, Tag: "GOLD", Name: "Golden Fixtures"}}
+
+	for i, vehicle := range goldenVehicles {
+		battles := careerBattles / len(goldenVehicles)
+		played := sessionBattles / len(goldenVehicles)
+		fixture.vehicles = append(fixture.vehicles, wg.VehicleStatsFrame{
+			TankID:         vehicle.ID,
+			Stats:          frame(battles + played),
+			LastBattleTime: 1700000000 + played*600 - i,
+			MarkOfMastery:  i,
+		})
+	}
+	return fixture
+}
+
+/*
+setupGoldenFixtures replaces the database with in-memory fixtures and returns a stats provider for the duration of a test
+*/
+func setupGoldenFixtures(t *testing.T) *wargaming.FixtureProvider {
+	t.Helper()
+
+	storage, provider := memory.NewStorage(), wargaming.NewFixtureProvider()
+
+	previousStorage, previousLocal := database.DefaultStorage, time.Local
+	t.Cleanup(func() {
+		database.DefaultStorage, time.Local = previousStorage, previousLocal
+	})
+	database.DefaultStorage, time.Local = storage, time.UTC
+
+	averages := make(map[int]core.ReducedStatsFrame)
+	for _, vehicle := range goldenVehicles {
+		averages[vehicle.ID] = core.ReducedStatsFrame{
+			Battles:              100,
+			BattlesWon:           50,
+			DamageDealt:          180000,
+			Frags:                100,
+			EnemiesSpotted:       100,
+			DroppedCapturePoints: 80,
+		}
+	}
+	err := errors.Join(storage.UpdateAverages(averages), storage.UpdateGlossary(goldenVehicles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return provider
+}
+
+func withGoldenBackground(img image.Image) image.Image {
+	background, _ := assets.GetImage("images/backgrounds/light")
+	return render.AddBackground(img, background, render.Style{Blur: 10, BorderRadius: 30})
+}
+
+/*
+Run task test:golden:update to regenerate the golden images after an intentional layout change.
+Run the tests with -update to regenerate the golden images after an intentional layout change.
+*/
+func assertGoldenImage(t *testing.T, name string, img image.Image) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".png")
+	if *updateGolden {
+		err := writePNG(path, img)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open golden image, run with -update to create it: %s", err)
+	}
+	defer f.Close()
+
+	golden, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := compareImages(golden, img)
+	if err == nil && changed > goldenTolerance {
+		err = fmt.Errorf("%.2f%% of pixels changed", changed*100)
+	}
+	if err != nil {
+		failedPath := filepath.Join("testdata", "golden", name+".failed.png")
+		_ = writePNG(failedPath, img)
+		t.Fatalf("%s does not match the golden image, output saved to %s: %s", name, failedPath, err)
+	}
+}
+
+/*
+compareImages returns the share of pixels where any channel differs by more than goldenPixelThreshold
+*/
+func compareImages(expected, actual image.Image) (float64, error) {
+	if expected.Bounds().Size() != actual.Bounds().Size() {
+		return 1, fmt.Errorf("size changed from %v to %v", expected.Bounds().Size(), actual.Bounds().Size())
+	}
+
+	var changed int
+	eb, ab := expected.Bounds(), actual.Bounds()
+	for y := 0; y < eb.Dy(); y++ {
+		for x := 0; x < eb.Dx(); x++ {
+			er, eg, ebl, ea := expected.At(eb.Min.X+x, eb.Min.Y+y).RGBA()
+			ar, ag, abl, aa := actual.At(ab.Min.X+x, ab.Min.Y+y).RGBA()
+			if max(channelDiff(er, ar), channelDiff(eg, ag), channelDiff(ebl, abl), channelDiff(ea, aa)) > goldenPixelThreshold {
+				changed++
+			}
+		}
+	}
+	return float64(changed) / float64(eb.Dx()*eb.Dy()), nil
+}
+
+func channelDiff(a, b uint32) uint32 {
+	// RGBA returns 16 bit channels
+	a, b = a>>8, b>>8
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func writePNG(path string, img image.Image) error {
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return png.Encode(f, img)
+}
@@ -3,16 +3,17 @@ package period
 import (
 	"github.com/cufee/aftermath-core/dataprep"
 	"github.com/cufee/aftermath-core/dataprep/period"
+	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/render/shared"
 )
 
-func statsBlocksToColumnBlock(style overviewStyle, statsBlocks []period.StatsBlock) (render.Block, error) {
+func statsBlocksToColumnBlock(style overviewStyle, statsBlocks []period.StatsBlock, printer localization.LocalePrinter) (render.Block, error) {
 	var content []render.Block
 
 	for _, statsBlock := range statsBlocks {
 		if statsBlock.Flavor == period.BlockFlavorSpecial {
-			content = append(content, uniqueStatsBlock(style, statsBlock))
+			content = append(content, uniqueStatsBlock(style, statsBlock, printer))
 		} else {
 			content = append(content, defaultStatsBlock(style, statsBlock))
 		}
@@ -20,10 +21,10 @@ func statsBlocksToColumnBlock(style overviewStyle, statsBlocks []period.StatsBlo
 	return render.NewBlocksContent(style.container, content...), nil
 }
 
-func uniqueStatsBlock(style overviewStyle, stats period.StatsBlock) render.Block {
+func uniqueStatsBlock(style overviewStyle, stats period.StatsBlock, printer localization.LocalePrinter) render.Block {
 	switch stats.Tag {
 	case dataprep.TagWN8:
-		return uniqueBlockWN8(style, stats)
+		return uniqueBlockWN8(style, stats, printer)
 	default:
 		return defaultStatsBlock(style, stats)
 	}
@@ -38,7 +39,7 @@ func defaultStatsBlock(style overviewStyle, stats period.StatsBlock) render.Bloc
 	return render.NewBlocksContent(style.blockContainer, blocks...)
 }
 
-func uniqueBlockWN8(style overviewStyle, stats period.StatsBlock) render.Block {
+func uniqueBlockWN8(style overviewStyle, stats period.StatsBlock, printer localization.LocalePrinter) render.Block {
 	var blocks []render.Block
 
 	valueStyle, labelStyle := style.block(stats)
@@ -63,7 +64,7 @@ func uniqueBlockWN8(style overviewStyle, stats period.StatsBlock) render.Block {
 			PaddingX:        10,
 			BorderRadius:    15,
 			BackgroundColor: ratingColors.Background,
-		}, render.NewTextContent(labelStyle, shared.GetWN8TierName(int(stats.Data.Value)))))
+		}, render.NewTextContent(labelStyle, shared.GetWN8TierName(int(stats.Data.Value), printer))))
 	}
 
 	return render.NewBlocksContent(render.Style{Direction: render.DirectionVertical, AlignItems: render.AlignItemsCenter, Gap: 10, PaddingY: 5}, blocks...)
@@ -7,6 +7,7 @@ import (
 	"github.com/cufee/aftermath-core/dataprep"
 	"github.com/cufee/aftermath-core/dataprep/period"
 	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/render/badges"
 	"github.com/cufee/aftermath-core/internal/logic/render/shared"
@@ -23,6 +24,8 @@ func generateCards(player PlayerData, options RenderOptions) ([]render.Block, er
 		return nil, errors.New("no cards provided")
 	}
 
+	printer := localization.GetPrinter(options.Locale)
+
 	// Calculate minimal card width to fit all the content
 	var cardWidth float64
 	overviewColumnWidth := float64(shared.DefaultLogoOptions().Width())
@@ -41,7 +44,7 @@ func generateCards(player PlayerData, options RenderOptions) ([]render.Block, er
 
 					label := block.Label
 					if block.Tag == dataprep.TagWN8 {
-						label = shared.GetWN8TierName(int(block.Data.Value))
+						label = shared.GetWN8TierName(int(block.Data.Value), printer)
 					}
 					labelSize := render.MeasureString(label, *labelStyle.Font)
 					valueSize := render.MeasureString(block.Data.String, *valueStyle.Font)
@@ -129,7 +132,7 @@ func generateCards(player PlayerData, options RenderOptions) ([]render.Block, er
 	{
 		var overviewCardBlocks []render.Block
 		for _, column := range player.Cards.Overview.Blocks {
-			columnBlock, err := statsBlocksToColumnBlock(getOverviewStyle(overviewColumnWidth), column)
+			columnBlock, err := statsBlocksToColumnBlock(getOverviewStyle(overviewColumnWidth), column, printer)
 			if err != nil {
 				return nil, err
 			}
@@ -7,6 +7,7 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/stats/period"
+	"golang.org/x/text/language"
 )
 
 type PlayerData struct {
@@ -17,6 +18,7 @@ type PlayerData struct {
 }
 
 type RenderOptions struct {
+	Locale    language.Tag
 	PromoText []string
 	CardStyle render.Style
 }
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"image"
 	"strings"
 	"time"
 
@@ -24,6 +25,8 @@ type PlayerData struct {
 
 	Subscriptions []models.UserSubscription
 	Cards         session.Cards
+
+	RatingLeagueIcon image.Image // Optional, rendered next to the rating overview card title
 }
 
 type RenderOptions struct {
@@ -86,6 +89,14 @@ func snapshotToCardsBlocks(player PlayerData, options RenderOptions) ([]render.B
 
 			}
 
+			if overview := player.Cards.RatingOverview; overview != nil {
+				for _, card := range player.Cards.Rating {
+					if card.Type == dataprep.CardTypeOverview {
+						cardWidth = helpers.Max(cardWidth, ratingOverviewMinWidth(card.Title, overview))
+					}
+				}
+			}
+
 			// Find the minimum required width to fix card content for the largest card
 			var totalContentSize float64
 			for _, size := range cardBlockSizes {
@@ -134,7 +145,13 @@ func snapshotToCardsBlocks(player PlayerData, options RenderOptions) ([]render.B
 
 	// Rating Cards
 	if len(player.Cards.Rating) > 0 {
-		ratingGroup, err := makeCardsGroup(player.Cards.Rating, cardWidth, cardBlockSizes)
+		ratingGroup, err := makeCardsGroup(player.Cards.Rating, cardWidth, cardBlockSizes, func(card session.Card, style render.Style, opts convertOptions) (render.Block, bool, error) {
+			if card.Type != dataprep.CardTypeOverview || player.Cards.RatingOverview == nil {
+				return render.Block{}, false, nil
+			}
+			block, err := newRatingOverviewCard(style, card, cardBlockSizes, opts, player.Cards.RatingOverview, player.RatingLeagueIcon, player.Account.ID)
+			return block, true, err
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -143,7 +160,7 @@ func snapshotToCardsBlocks(player PlayerData, options RenderOptions) ([]render.B
 
 	// Unrated Cards
 	if len(player.Cards.Unrated) > 0 {
-		unratedGroup, err := makeCardsGroup(player.Cards.Unrated, cardWidth, cardBlockSizes)
+		unratedGroup, err := makeCardsGroup(player.Cards.Unrated, cardWidth, cardBlockSizes, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -176,7 +193,10 @@ func snapshotToCardsBlocks(player PlayerData, options RenderOptions) ([]render.B
 	return cards, nil
 }
 
-func makeCardsGroup(cards []session.Card, cardWidth float64, cardBlockSizes map[int]float64) (render.Block, error) {
+/*
+makeCardsGroup renders all cards into a single group, customCard can optionally render a card instead of the default vehicle card.
+*/
+func makeCardsGroup(cards []session.Card, cardWidth float64, cardBlockSizes map[int]float64, customCard func(session.Card, render.Style, convertOptions) (render.Block, bool, error)) (render.Block, error) {
 	var groupCards []render.Block
 
 	for _, card := range cards {
@@ -195,6 +215,17 @@ func makeCardsGroup(cards []session.Card, cardWidth float64, cardBlockSizes map[
 			opts = convertOptions{true, hasCareer, false, hasCareer && hasSession, 0}
 		}
 
+		if customCard != nil {
+			block, ok, err := customCard(card, defaultCardStyle(cardWidth), opts)
+			if err != nil {
+				return render.Block{}, err
+			}
+			if ok {
+				groupCards = append(groupCards, block)
+				continue
+			}
+		}
+
 		card, err := newVehicleCard(defaultCardStyle(cardWidth), card, cardBlockSizes, opts)
 		if err != nil {
 			return render.Block{}, err
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/cufee/aftermath-core/dataprep/session"
+	helpers "github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/logic/render"
+)
+
+var (
+	ratingLeagueIconSize     = 30.0
+	ratingHeaderStyle        = render.Style{Direction: render.DirectionHorizontal, AlignItems: render.AlignItemsCenter, Gap: 10}
+	ratingPositionStyle      = render.Style{Font: &render.FontMedium, FontColor: render.TextPrimary}
+	ratingNeighborStyle      = render.Style{Font: &render.FontSmall, FontColor: render.TextAlt}
+	ratingNeighborSelfStyle  = render.Style{Font: &render.FontSmall, FontColor: render.TextPrimary}
+	ratingNeighborsListStyle = render.Style{Direction: render.DirectionVertical, Gap: 2, PaddingY: 10}
+	ratingNeighborRowGap     = 20.0
+)
+
+/*
+ratingOverviewMinWidth returns the minimal card width required to fit the rating overview header and neighbors
+*/
+func ratingOverviewMinWidth(title string, overview *session.RatingOverview) float64 {
+	titleSize := render.MeasureString(title, *defaultBlockStyle.career.Font)
+	labelSize := render.MeasureString(overview.Label, *ratingPositionStyle.Font)
+	width := titleSize.TotalWidth + labelSize.TotalWidth + ratingLeagueIconSize + ratingHeaderStyle.Gap*2
+
+	for _, neighbor := range overview.Neighbors {
+		nameSize := render.MeasureString(neighborName(neighbor), *ratingNeighborStyle.Font)
+		scoreSize := render.MeasureString(fmt.Sprint(neighbor.Score), *ratingNeighborStyle.Font)
+		width = helpers.Max(width, nameSize.TotalWidth+scoreSize.TotalWidth+ratingNeighborRowGap)
+	}
+
+	return width + defaultCardStyle(0).PaddingX*4
+}
+
+func newRatingOverviewCard(style render.Style, card session.Card, sizes map[int]float64, opts convertOptions, overview *session.RatingOverview, leagueIcon image.Image, accountID int) (render.Block, error) {
+	blocks, err := statsBlocksToCardBlocks(card.Blocks, sizes, opts)
+	if err != nil {
+		return render.Block{}, err
+	}
+	contentWidth := style.Width - style.PaddingX*2
+
+	var headerBlocks []render.Block
+	if leagueIcon != nil {
+		headerBlocks = append(headerBlocks, render.NewImageContent(render.Style{Width: ratingLeagueIconSize, Height: ratingLeagueIconSize}, leagueIcon))
+	}
+	headerBlocks = append(headerBlocks, newCardTitle(card.Title))
+	if overview.Label != "" {
+		headerBlocks = append(headerBlocks, render.NewTextContent(ratingPositionStyle, overview.Label))
+	}
+
+	cardContentBlocks := []render.Block{render.NewBlocksContent(ratingHeaderStyle, headerBlocks...)}
+	cardContentBlocks = append(cardContentBlocks, render.NewBlocksContent(statsRowStyle(contentWidth), blocks...))
+
+	if len(overview.Neighbors) > 0 {
+		var rows []render.Block
+		for _, neighbor := range overview.Neighbors {
+			textStyle := ratingNeighborStyle
+			if neighbor.AccountID == accountID {
+				textStyle = ratingNeighborSelfStyle
+			}
+			rows = append(rows, render.NewBlocksContent(
+				render.Style{Direction: render.DirectionHorizontal, JustifyContent: render.JustifyContentSpaceBetween, AlignItems: render.AlignItemsCenter, Width: contentWidth},
+				render.NewTextContent(textStyle, neighborName(neighbor)),
+				render.NewTextContent(textStyle, fmt.Sprint(neighbor.Score)),
+			))
+		}
+
+		listStyle := ratingNeighborsListStyle
+		listStyle.Width = contentWidth
+		cardContentBlocks = append(cardContentBlocks, render.NewBlocksContent(listStyle, rows...))
+	}
+
+	return render.NewBlocksContent(style, cardContentBlocks...), nil
+}
+
+func neighborName(neighbor session.RatingNeighbor) string {
+	if neighbor.ClanTag != "" {
+		return fmt.Sprintf("#%d %s [%s]", neighbor.Position, neighbor.Nickname, neighbor.ClanTag)
+	}
+	return fmt.Sprintf("#%d %s", neighbor.Position, neighbor.Nickname)
+}
@@ -2,6 +2,9 @@ package shared
 
 import (
 	"image/color"
+	"math"
+
+	"github.com/cufee/aftermath-core/internal/core/localization"
 )
 
 type ratingColors struct {
@@ -43,36 +46,40 @@ func GetWN8Colors(r int) ratingColors {
 	return ratingColors{color.Transparent, color.Transparent}
 }
 
-func GetWN8TierName(r int) string {
-	if r > 0 && r < 301 {
-		return "Very Bad"
-	}
-	if r > 300 && r < 451 {
-		return "Bad"
-	}
-	if r > 450 && r < 651 {
-		return "Below Average"
-	}
-	if r > 650 && r < 901 {
-		return "Average"
-	}
-	if r > 900 && r < 1201 {
-		return "Above Average"
-	}
-	if r > 1200 && r < 1601 {
-		return "Good"
-	}
-	if r > 1600 && r < 2001 {
-		return "Very Good"
-	}
-	if r > 2000 && r < 2451 {
-		return "Great"
-	}
-	if r > 2450 && r < 2901 {
-		return "Unicum"
-	}
-	if r > 2900 {
-		return "Super Unicum"
+type WN8Tier struct {
+	Max   int
+	Label string
+	Name  string // Used when the label is missing from the bundles
+}
+
+/*
+WN8Tiers are sorted by the highest rating included in each tier, the last tier has no upper bound
+*/
+var WN8Tiers = []WN8Tier{
+	{300, "label_wn8_tier_very_bad", "Very Bad"},
+	{450, "label_wn8_tier_bad", "Bad"},
+	{650, "label_wn8_tier_below_average", "Below Average"},
+	{900, "label_wn8_tier_average", "Average"},
+	{1200, "label_wn8_tier_above_average", "Above Average"},
+	{1600, "label_wn8_tier_good", "Good"},
+	{2000, "label_wn8_tier_very_good", "Very Good"},
+	{2450, "label_wn8_tier_great", "Great"},
+	{2900, "label_wn8_tier_unicum", "Unicum"},
+	{math.MaxInt, "label_wn8_tier_super_unicum", "Super Unicum"},
+}
+
+func GetWN8TierName(r int, printer localization.LocalePrinter) string {
+	if r < 1 {
+		return ""
+	}
+	for _, tier := range WN8Tiers {
+		if r > tier.Max {
+			continue
+		}
+		if name := printer(tier.Label); !localization.IsMissing(name) {
+			return name
+		}
+		return tier.Name
 	}
 	return ""
 }
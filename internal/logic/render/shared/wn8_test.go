@@ -0,0 +1,26 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/cufee/aftermath-core/internal/core/localization"
+	"golang.org/x/text/language"
+)
+
+func TestGetWN8TierName(t *testing.T) {
+	printer := localization.GetPrinter(language.German)
+	if name := GetWN8TierName(100, printer); name != "Sehr schlecht" {
+		t.Errorf("expected a localized tier name, got %q", name)
+	}
+
+	// Tier names never render as raw message IDs
+	missing := func(id string) string { return "? " + id }
+	for _, tier := range WN8Tiers {
+		if name := GetWN8TierName(tier.Max, missing); name != tier.Name {
+			t.Errorf("expected %q for rating %d, got %q", tier.Name, tier.Max, name)
+		}
+	}
+	if name := GetWN8TierName(0, printer); name != "" {
+		t.Errorf("expected no tier for unrated players, got %q", name)
+	}
+}
@@ -1,47 +1,114 @@
 package scheduler
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/cufee/aftermath-core/internal/logic/scheduler/schedule"
 	"github.com/go-co-op/gocron"
 	"github.com/rs/zerolog/log"
 )
 
-var (
-// SessionsNA =
-)
+var ErrUnknownJob = errors.New("unknown job")
 
-func StartCronJobs() {
-	log.Info().Msg("starting cron jobs")
+/*
+Job is a worker function running on a named schedule from the schedule registry
+*/
+type Job struct {
+	Name     string
+	Schedule string
+	run      func()
+}
 
-	c := gocron.NewScheduler(time.UTC)
-	// Tasks
-	c.Cron("* * * * *").Do(runTasksWorker)
-	c.Cron("0 * * * *").Do(restartTasksWorker)
+type JobStatus struct {
+	Name       string    `json:"name"`
+	Schedule   string    `json:"schedule"`
+	Expression string    `json:"expression"`
+	NextRun    time.Time `json:"nextRun"`
+}
 
-	// Glossary - Do it around the same time WG releases game updates
-	c.Cron("0 10 * * *").Do(updateGlossaryWorker)
-	c.Cron("0 12 * * *").Do(updateGlossaryWorker)
-	// c.AddFunc("40 9 * * 0", updateAchievementsWorker)
+var jobs = []Job{
+	// Tasks
+	{Name: "tasks.run", Schedule: "tasks.run", run: runTasksWorker},
+	{Name: "tasks.restart", Schedule: "tasks.restart", run: restartTasksWorker},
 
-	// Averages - Update averages shortly after session refreshes
-	c.Cron("0 10 * * *").Do(updateAveragesWorker)
-	c.Cron("0 2 * * *").Do(updateAveragesWorker)
-	c.Cron("0 19 * * *").Do(updateAveragesWorker)
+	// Glossary
+	{Name: "glossary.vehicles", Schedule: "glossary.vehicles", run: updateGlossaryWorker},
+	{Name: "glossary.achievements", Schedule: "glossary.achievements", run: updateAchievementsWorker},
 
-	// Sessions
-	c.Cron("0 9 * * *").Do(createSessionTasksWorker("NA"))  // NA
-	c.Cron("0 1 * * *").Do(createSessionTasksWorker("EU"))  // EU
-	c.Cron("0 18 * * *").Do(createSessionTasksWorker("AS")) // Asia
+	// Sessions for users with a custom reset time
+	{Name: "sessions.custom", Schedule: "sessions.custom", run: createSessionResetTasksWorker},
 
-	// Refresh WN8
-	// "45 9 * * *" 	// NA
-	// "45 1 * * *" 	// EU
-	// "45 18 * * *" 	// Asia
+	// Averages
+	{Name: "averages", Schedule: "averages", run: updateAveragesWorker},
 
 	// Configurations
-	c.Cron("0 0 */7 * *").Do(rotateBackgroundPresetsWorker)
+	{Name: "backgrounds.rotate", Schedule: "backgrounds.rotate", run: rotateBackgroundPresetsWorker},
+}
+
+func init() {
+	for _, realm := range []string{"NA", "EU", "AS"} {
+		suffix := "." + strings.ToLower(realm)
+		jobs = append(jobs,
+			Job{Name: "sessions" + suffix, Schedule: schedule.SessionReset(realm), run: createSessionTasksWorker(realm)},
+			// Achievements - Snapshots are recorded alongside sessions so that the session diff includes medals
+			Job{Name: "achievements" + suffix, Schedule: schedule.SessionReset(realm), run: createAchievementsTasksWorker(realm)},
+			Job{Name: "rating" + suffix, Schedule: "rating" + suffix, run: ratingSeasonWorker(realm)},
+			Job{Name: "clans" + suffix, Schedule: "clans" + suffix, run: createClanTasksWorker(realm)},
+			Job{Name: "wn8" + suffix, Schedule: "wn8" + suffix, run: createWN8TasksWorker(realm)},
+			Job{Name: "rollups" + suffix, Schedule: "rollups" + suffix, run: createRollupTasksWorker(realm)},
+		)
+	}
+	slices.SortFunc(jobs, func(a, b Job) int { return strings.Compare(a.Name, b.Name) })
+}
+
+func StartCronJobs() {
+	log.Info().Msg("starting cron jobs")
+
+	c := gocron.NewScheduler(time.UTC)
+	for _, job := range jobs {
+		s, ok := schedule.Get(job.Schedule)
+		if !ok {
+			log.Error().Str("job", job.Name).Str("schedule", job.Schedule).Msg("job schedule not found")
+			continue
+		}
+		_, err := c.Cron(s.Expression).Tag(job.Name).Do(job.run)
+		if err != nil {
+			log.Err(err).Str("job", job.Name).Msg("failed to schedule a job")
+		}
+	}
 
 	// Start the Cron job scheduler
 	c.StartAsync()
 }
+
+/*
+Jobs returns all registered jobs with their next run time
+*/
+func Jobs() []JobStatus {
+	now := time.Now()
+
+	var status []JobStatus
+	for _, job := range jobs {
+		s, _ := schedule.Get(job.Schedule)
+		next, _ := schedule.Next(job.Schedule, now)
+		status = append(status, JobStatus{Name: job.Name, Schedule: job.Schedule, Expression: s.Expression, NextRun: next})
+	}
+	return status
+}
+
+/*
+RunJob starts a job in the background outside of its schedule
+*/
+func RunJob(name string) error {
+	for _, job := range jobs {
+		if job.Name == name {
+			log.Info().Str("job", name).Msg("running a job manually")
+			go job.run()
+			return nil
+		}
+	}
+	return ErrUnknownJob
+}
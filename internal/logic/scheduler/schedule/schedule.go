@@ -0,0 +1,262 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/gorhill/cronexpr"
+)
+
+var (
+	ErrUnknownSchedule     = errors.New("unknown schedule")
+	ErrInvalidCronExpr     = errors.New("invalid cron expression")
+	ErrScheduleOverrideFmt = errors.New("invalid schedule override, expected name=expression")
+)
+
+/*
+ConfigurationKey is the key in the configuration collection holding schedule overrides, the value is a map of schedule name to cron expression
+*/
+const ConfigurationKey = "schedules"
+
+/*
+Defaults are used for any schedule that has no override, all times are in UTC
+*/
+var Defaults = map[string]string{
+	"tasks.run":     "* * * * *",
+	"tasks.restart": "*/5 * * * *",
+
+	// Glossary - Do it around the same time WG releases game updates
+	"glossary.vehicles":     "0 10,12 * * *",
+	"glossary.achievements": "40 9 * * 0",
+
+	// Averages - Update averages shortly after session refreshes
+	"averages": "0 2,10,19 * * *",
+
+	// Sessions - Achievements snapshots share these schedules, rating/WN8 below are separate and need to be overridden along with them
+	"sessions.na": "0 9 * * *",
+	"sessions.eu": "0 1 * * *",
+	"sessions.as": "0 18 * * *",
+	// Users with a custom reset time are grouped into hourly buckets
+	"sessions.custom": "0 * * * *",
+
+	// Rating - Snapshots and season close out, 15 minutes after the default session reset
+	"rating.na": "15 9 * * *",
+	"rating.eu": "15 1 * * *",
+	"rating.as": "15 18 * * *",
+
+	// Clans - Refresh members ahead of session resets
+	"clans.na": "30 8 * * *",
+	"clans.eu": "30 0 * * *",
+	"clans.as": "30 17 * * *",
+
+	// WN8 - Career history, 45 minutes after the default session reset
+	"wn8.na": "45 9 * * *",
+	"wn8.eu": "45 1 * * *",
+	"wn8.as": "45 18 * * *",
+
+	// Rollups - Compact daily sessions once session refresh tasks had time to finish
+	"rollups.na": "0 12 * * *",
+	"rollups.eu": "0 4 * * *",
+	"rollups.as": "0 21 * * *",
+
+	"backgrounds.rotate": "0 0 */7 * *",
+}
+
+/*
+SessionReset returns the name of the schedule used for session resets on a realm
+*/
+func SessionReset(realm string) string {
+	return "sessions." + strings.ToLower(realm)
+}
+
+type Schedule struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+	Default    string `json:"default"`
+
+	expr *cronexpr.Expression
+}
+
+func (s Schedule) Next(from time.Time) time.Time {
+	return s.expr.Next(from.UTC())
+}
+
+/*
+Registry holds all known schedules, only names present in the defaults can be overridden
+*/
+type Registry struct {
+	mu        sync.RWMutex
+	schedules map[string]Schedule
+}
+
+func NewRegistry(defaults map[string]string) (*Registry, error) {
+	registry := &Registry{schedules: make(map[string]Schedule, len(defaults))}
+	for name, expression := range defaults {
+		expr, err := parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		registry.schedules[name] = Schedule{Name: name, Expression: expression, Default: expression, expr: expr}
+	}
+	return registry, nil
+}
+
+/*
+Apply validates and applies all overrides, nothing is changed if any of them are invalid
+*/
+func (r *Registry) Apply(overrides map[string]string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated := make(map[string]Schedule, len(overrides))
+	for name, expression := range overrides {
+		current, ok := r.schedules[name]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownSchedule, name)
+		}
+		expr, err := parse(expression)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		current.Expression = expression
+		current.expr = expr
+		updated[name] = current
+	}
+	for name, schedule := range updated {
+		r.schedules[name] = schedule
+	}
+	return nil
+}
+
+func (r *Registry) Get(name string) (Schedule, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, ok := r.schedules[name]
+	return schedule, ok
+}
+
+/*
+All returns all schedules sorted by name
+*/
+func (r *Registry) All() []Schedule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var schedules []Schedule
+	for _, schedule := range r.schedules {
+		schedules = append(schedules, schedule)
+	}
+	slices.SortFunc(schedules, func(a, b Schedule) int { return strings.Compare(a.Name, b.Name) })
+	return schedules
+}
+
+func (r *Registry) Next(name string, from time.Time) (time.Time, error) {
+	schedule, ok := r.Get(name)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrUnknownSchedule, name)
+	}
+	return schedule.Next(from), nil
+}
+
+/*
+parse only accepts standard 5 field expressions, cronexpr would also accept seconds and years which the scheduler does not support
+*/
+func parse(expression string) (*cronexpr.Expression, error) {
+	if len(strings.Fields(expression)) != 5 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCronExpr, expression)
+	}
+	expr, err := cronexpr.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCronExpr, expression)
+	}
+	return expr, nil
+}
+
+var Default *Registry
+
+func init() {
+	var err error
+	Default, err = NewRegistry(Defaults)
+	if err != nil {
+		panic(err)
+	}
+}
+
+/*
+Load applies overrides to the default registry in order of precedence - SCHEDULE_CONFIG_PATH file, SCHEDULE_OVERRIDES and the configuration collection
+*/
+func Load() error {
+	if path := os.Getenv("SCHEDULE_CONFIG_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var overrides map[string]string
+		err = json.Unmarshal(data, &overrides)
+		if err != nil {
+			return err
+		}
+		err = Default.Apply(overrides)
+		if err != nil {
+			return err
+		}
+	}
+
+	if value := os.Getenv("SCHEDULE_OVERRIDES"); value != "" {
+		overrides, err := ParseOverrides(value)
+		if err != nil {
+			return err
+		}
+		err = Default.Apply(overrides)
+		if err != nil {
+			return err
+		}
+	}
+
+	configuration, err := database.GetAppConfiguration[map[string]string](ConfigurationKey)
+	if err != nil {
+		if errors.Is(err, database.ErrConfigurationNotFound) {
+			return nil
+		}
+		return err
+	}
+	return Default.Apply(configuration.Value)
+}
+
+/*
+ParseOverrides parses a semicolon separated list of name=expression pairs, cron expressions can contain commas
+*/
+func ParseOverrides(value string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, pair := range strings.Split(value, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, expression, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrScheduleOverrideFmt, pair)
+		}
+		overrides[strings.TrimSpace(name)] = strings.TrimSpace(expression)
+	}
+	return overrides, nil
+}
+
+func Get(name string) (Schedule, bool) {
+	return Default.Get(name)
+}
+
+func All() []Schedule {
+	return Default.All()
+}
+
+func Next(name string, from time.Time) (time.Time, error) {
+	return Default.Next(name, from)
+}
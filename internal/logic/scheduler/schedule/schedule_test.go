@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistryApply(t *testing.T) {
+	registry, err := NewRegistry(Defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	next, err := registry.Next(SessionReset("NA"), from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, next)
+	}
+
+	err = registry.Apply(map[string]string{"sessions.na": "0 7 * * *", "sessions.eu": "not a cron"})
+	if !errors.Is(err, ErrInvalidCronExpr) {
+		t.Fatalf("expected ErrInvalidCronExpr, got %v", err)
+	}
+	if s, _ := registry.Get("sessions.na"); s.Expression != Defaults["sessions.na"] {
+		t.Fatalf("invalid overrides should not be partially applied, got %s", s.Expression)
+	}
+
+	err = registry.Apply(map[string]string{"sessions.xx": "0 7 * * *"})
+	if !errors.Is(err, ErrUnknownSchedule) {
+		t.Fatalf("expected ErrUnknownSchedule, got %v", err)
+	}
+
+	overrides, err := ParseOverrides("sessions.na=0 7 * * *; averages = 0 1,3 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = registry.Apply(overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ = registry.Next(SessionReset("na"), from)
+	if want := time.Date(2024, 5, 2, 7, 0, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("expected %s, got %s", want, next)
+	}
+	if s, _ := registry.Get("averages"); s.Expression != "0 1,3 * * *" || s.Default != Defaults["averages"] {
+		t.Fatalf("unexpected averages schedule %+v", s)
+	}
+}
@@ -0,0 +1,48 @@
+package tasks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cufee/aftermath-core/internal/logic/cache"
+)
+
+func init() {
+	registerTaskHandler(TaskRecordPlayerAchievements, TaskHandler{
+		Process: func(task *Task) (string, error) {
+			if task.Data == nil {
+				return "no data provided", errNoTaskData
+			}
+			realm, ok := task.Data["realm"].(string)
+			if !ok {
+				return "invalid realm", errInvalidRealm
+			}
+
+			// Tasks created for a custom reset time record snapshots with a reference to that reset
+			reference, _ := task.Data["reference"].(string)
+
+			missing, err := cache.RecordAccountsAchievements(realm, reference, task.Targets...)
+			if err != nil {
+				return "failed to record achievements on all accounts", err
+			}
+			if len(missing) > 0 {
+				// Private accounts will never return achievements, there is no point in retrying them
+				return fmt.Sprintf("finished achievements update, %d accounts had no data", len(missing)), nil
+			}
+			return "finished achievements update on all accounts", nil
+		},
+	})
+}
+
+func CreateAchievementsSnapshotTasks(realm string) error {
+	realm = strings.ToUpper(realm)
+	task := Task{
+		Type:     TaskRecordPlayerAchievements,
+		Priority: TaskPriorityLow,
+		Data: map[string]any{
+			"realm": realm,
+		},
+	}
+	// This update requires 1 request per 100 players
+	return CreateBulkTask(realm, task, splitTasksByTargets(100))
+}
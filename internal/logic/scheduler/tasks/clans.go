@@ -0,0 +1,41 @@
+package tasks
+
+import (
+	"strings"
+
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/cache"
+)
+
+func init() {
+	registerTaskHandler(TaskUpdateClans, TaskHandler{
+		Process: func(task *Task) (string, error) {
+			if task.Data == nil {
+				return "no data provided", errNoTaskData
+			}
+			realm, ok := task.Data["realm"].(string)
+			if !ok {
+				return "invalid realm", errInvalidRealm
+			}
+
+			err := cache.UpdateClansCache(wargaming.Clients.Cache, realm, task.Targets...)
+			if err != nil {
+				return "failed to update clans", err
+			}
+			return "finished clan update on all clans", nil
+		},
+	})
+}
+
+func CreateClanUpdateTasks(realm string) error {
+	realm = strings.ToUpper(realm)
+	task := Task{
+		Type:     TaskUpdateClans,
+		Priority: TaskPriorityNormal,
+		Data: map[string]any{
+			"realm": realm,
+		},
+	}
+	// This update requires 1 request per 100 clans
+	return CreateBulkTask(realm, task, splitTasksByTargets(100))
+}
@@ -4,15 +4,23 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/rs/zerolog/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-var DefaultQueue = NewQueue(10)
+var DefaultQueue = NewQueue(10, 4)
 
+/*
+Queue runs tasks concurrently up to concurrencyLimit, tasks for a single realm are additionally limited to realmConcurrencyLimit so that one realm can not take up every worker
+*/
 type Queue struct {
-	concurrencyLimit int
-	limiter          chan struct{}
-	lastTaskRun      time.Time
+	concurrencyLimit      int
+	realmConcurrencyLimit int
+	limiter               chan struct{}
+	realmLimiters         map[string]chan struct{}
+	realmLimitersMu       sync.Mutex
+	lastTaskRun           time.Time
 }
 
 func (q *Queue) ConcurrencyLimit() int {
@@ -27,13 +35,27 @@ func (q *Queue) LastTaskRun() time.Time {
 	return q.lastTaskRun
 }
 
-func NewQueue(concurrencyLimit int) *Queue {
+func NewQueue(concurrencyLimit, realmConcurrencyLimit int) *Queue {
 	return &Queue{
-		concurrencyLimit: concurrencyLimit,
-		limiter:          make(chan struct{}, concurrencyLimit),
+		concurrencyLimit:      concurrencyLimit,
+		realmConcurrencyLimit: min(realmConcurrencyLimit, concurrencyLimit),
+		limiter:               make(chan struct{}, concurrencyLimit),
+		realmLimiters:         make(map[string]chan struct{}),
 	}
 }
 
+func (q *Queue) realmLimiter(realm string) chan struct{} {
+	q.realmLimitersMu.Lock()
+	defer q.realmLimitersMu.Unlock()
+
+	limiter, ok := q.realmLimiters[realm]
+	if !ok {
+		limiter = make(chan struct{}, q.realmConcurrencyLimit)
+		q.realmLimiters[realm] = limiter
+	}
+	return limiter
+}
+
 func (q *Queue) Process(callback func(error), tasks ...Task) {
 	var err error
 	if callback != nil {
@@ -46,22 +68,29 @@ func (q *Queue) Process(callback func(error), tasks ...Task) {
 
 	log.Debug().Msgf("processing %d tasks", len(tasks))
 
+	stopRenewing := renewLeases(tasks)
+	defer stopRenewing()
+
 	var wg sync.WaitGroup
 	q.lastTaskRun = time.Now()
 	processedTasks := make(chan Task, len(tasks))
 	for _, task := range tasks {
 		wg.Add(1)
 		go func(t Task) {
+			// Realm slot is acquired first, otherwise tasks waiting on a busy realm would hold workers other realms could use
+			realmLimiter := q.realmLimiter(t.Realm())
+			realmLimiter <- struct{}{}
 			q.limiter <- struct{}{}
 			defer func() {
 				processedTasks <- t
 				wg.Done()
 				<-q.limiter
+				<-realmLimiter
 				log.Debug().Msgf("finished processing task %s", t.ID)
 			}()
 			log.Debug().Msgf("processing task %s", t.ID)
 
-			comment, err := t.Process()
+			comment, err := processTask(&t)
 			attempt := AttemptLog{
 				Timestamp: time.Now(),
 				Targets:   t.Targets,
@@ -69,12 +98,11 @@ func (q *Queue) Process(callback func(error), tasks ...Task) {
 			}
 			if err != nil {
 				attempt.Error = err.Error()
-				t.Status = TaskStatusFailed
-			}
-
-			if t.Status != TaskStatusFailed {
+				resolveFailedTask(&t, err)
+			} else {
 				t.Status = TaskStatusComplete
 			}
+			t.LeaseExpiresAt = time.Time{}
 			t.LogAttempt(attempt)
 		}(task)
 	}
@@ -85,13 +113,13 @@ func (q *Queue) Process(callback func(error), tasks ...Task) {
 	rescheduledCount := 0
 	processedSlice := make([]Task, 0, len(processedTasks))
 	for task := range processedTasks {
-		if task.Status == TaskStatusFailed && task.RetryOnFail() {
+		if task.Status == TaskStatusScheduled {
 			rescheduledCount++
-			task.Status = TaskStatusScheduled
 		}
 		processedSlice = append(processedSlice, task)
 	}
 
+	stopRenewing()
 	err = UpdateTasks(processedSlice...)
 	if err != nil {
 		return
@@ -99,3 +127,33 @@ func (q *Queue) Process(callback func(error), tasks ...Task) {
 
 	log.Debug().Msgf("processed %d tasks, %d rescheduled", len(processedSlice), rescheduledCount)
 }
+
+/*
+renewLeases keeps extending leases on tasks until the returned function is called, the function is safe to call more than once
+*/
+func renewLeases(tasks []Task) func() {
+	var ids []primitive.ObjectID
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(TaskLeaseDuration / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				err := database.RenewTaskLeases(WorkerID, TaskLeaseDuration, ids...)
+				if err != nil {
+					log.Err(err).Msg("failed to renew task leases")
+				}
+			}
+		}
+	}()
+
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,79 @@
+package tasks
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/cache"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
+)
+
+// The final snapshot of a season is recorded an hour before it ends, all retries need to fit in that window
+var ratingSnapshotRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Minute * 2,
+	MaxDelay:    time.Minute * 10,
+	Jitter:      0.2,
+}
+
+func init() {
+	registerTaskHandler(TaskRecordRatingSnapshots, TaskHandler{
+		RetryPolicy: &ratingSnapshotRetryPolicy,
+		Process: func(task *Task) (string, error) {
+			if task.Data == nil {
+				return "no data provided", errNoTaskData
+			}
+			realm, ok := task.Data["realm"].(string)
+			if !ok {
+				return "invalid realm", errInvalidRealm
+			}
+
+			season, err := wotblitz.GetCurrentRatingSeason(realm)
+			if err != nil {
+				return "failed to get current rating season", err
+			}
+			if finish := season.Finish(); !finish.IsZero() && finish.Before(time.Now()) {
+				// Leaderboards are frozen until the next season starts
+				return "rating season is over", nil
+			}
+
+			accountErrs, err := cache.RecordRatingSnapshots(wargaming.Clients.Cache, realm, season.SeasonID, task.Targets...)
+			if err != nil {
+				return "failed to record rating snapshots on all accounts", err
+			}
+
+			var failedAccounts []int
+			for accountId, err := range accountErrs {
+				if err != nil && accountId != 0 {
+					failedAccounts = append(failedAccounts, accountId)
+				}
+			}
+			if len(failedAccounts) == 0 {
+				return "finished rating snapshots on all accounts", nil
+			}
+
+			// Retry failed accounts
+			task.Targets = failedAccounts
+			return "retrying failed accounts", errors.New("some accounts failed")
+		},
+	})
+}
+
+/*
+CreateRatingSnapshotTasks creates tasks to record rating snapshots for all accounts on a realm, tasks will not start before scheduledAfter.
+*/
+func CreateRatingSnapshotTasks(realm string, scheduledAfter time.Time) error {
+	realm = strings.ToUpper(realm)
+	task := Task{
+		Type:           TaskRecordRatingSnapshots,
+		Priority:       TaskPriorityNormal,
+		ScheduledAfter: scheduledAfter,
+		Data: map[string]any{
+			"realm": realm,
+		},
+	}
+	// This update requires (1 + n) requests per n players, but only for players who played rating battles
+	return CreateBulkTask(realm, task, splitTasksByTargets(50))
+}
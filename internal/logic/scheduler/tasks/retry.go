@@ -0,0 +1,100 @@
+package tasks
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+/*
+RetryPolicy controls how failed tasks of a given type are rescheduled.
+Attempts are counted across the whole lifetime of a task, a task that failed MaxAttempts times is moved to TaskStatusDeadLetter.
+*/
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // Delay before the first retry, doubled on every following attempt
+	MaxDelay    time.Duration
+	Jitter      float64 // Fraction of the delay that is randomized, 0.2 results in a delay within ±20%
+
+	// Retryable classifies errors returned by a task handler, errors wrapped with Permanent are never retried
+	Retryable func(error) bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Minute * 5,
+	MaxDelay:    time.Hour,
+	Jitter:      0.2,
+}
+
+/*
+Backoff returns the delay before the next attempt, attempts is the number of attempts made so far
+*/
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += time.Duration(float64(delay) * p.Jitter * (rand.Float64()*2 - 1))
+	}
+	return delay
+}
+
+func (p RetryPolicy) IsRetryable(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return true
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+/*
+Permanent marks an error as not retryable, tasks failing with this error are not rescheduled
+*/
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err}
+}
+
+/*
+resolveFailedTask applies the retry policy of a task handler to a task that failed with err
+*/
+func resolveFailedTask(t *Task, err error) {
+	policy := DefaultRetryPolicy
+	if handler, ok := taskHandlers[t.Type]; ok && handler.RetryPolicy != nil {
+		policy = *handler.RetryPolicy
+	}
+
+	switch {
+	case !policy.IsRetryable(err):
+		t.Status = TaskStatusFailed
+		t.FailedTargets = t.Targets
+	case t.Attempts >= policy.MaxAttempts:
+		t.Status = TaskStatusDeadLetter
+		t.FailedTargets = t.Targets
+	default:
+		t.Status = TaskStatusScheduled
+		t.ScheduledAfter = time.Now().Add(policy.Backoff(t.Attempts))
+	}
+}
@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Minute, MaxDelay: time.Minute * 5}
+
+	expected := []time.Duration{time.Minute, time.Minute * 2, time.Minute * 4, time.Minute * 5, time.Minute * 5}
+	for i, delay := range expected {
+		if backoff := policy.Backoff(i + 1); backoff != delay {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, delay, backoff)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if backoff := policy.Backoff(1); backoff < time.Second*30 || backoff > time.Second*90 {
+			t.Fatalf("expected backoff within jitter range, got %s", backoff)
+		}
+	}
+}
+
+func TestResolveFailedTask(t *testing.T) {
+	task := Task{Type: "unknown", Targets: []int{1, 2}, Attempts: 1}
+	resolveFailedTask(&task, errors.New("temporary"))
+	if task.Status != TaskStatusScheduled || !task.ScheduledAfter.After(time.Now()) {
+		t.Errorf("expected task to be rescheduled, got %s at %s", task.Status, task.ScheduledAfter)
+	}
+
+	task.Attempts = DefaultRetryPolicy.MaxAttempts
+	resolveFailedTask(&task, errors.New("temporary"))
+	if task.Status != TaskStatusDeadLetter || len(task.FailedTargets) != 2 {
+		t.Errorf("expected task to be dead-lettered with failed targets, got %s %v", task.Status, task.FailedTargets)
+	}
+
+	task = Task{Type: "unknown", Targets: []int{1}, Attempts: 1}
+	resolveFailedTask(&task, errInvalidRealm)
+	if task.Status != TaskStatusFailed {
+		t.Errorf("expected permanent error to fail the task, got %s", task.Status)
+	}
+}
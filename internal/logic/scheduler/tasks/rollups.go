@@ -0,0 +1,39 @@
+package tasks
+
+import (
+	"strings"
+
+	"github.com/cufee/aftermath-core/internal/logic/cache"
+)
+
+func init() {
+	registerTaskHandler(TaskCompactSessionRollups, TaskHandler{
+		Process: func(task *Task) (string, error) {
+			if task.Data == nil {
+				return "no data provided", errNoTaskData
+			}
+			if _, ok := task.Data["realm"].(string); !ok {
+				return "invalid realm", errInvalidRealm
+			}
+
+			err := cache.CompactSessionRollups(task.Targets...)
+			if err != nil {
+				return "failed to compact session rollups", err
+			}
+			return "compacted session rollups on all accounts", nil
+		},
+	})
+}
+
+func CreateSessionRollupTasks(realm string) error {
+	realm = strings.ToUpper(realm)
+	task := Task{
+		Type:     TaskCompactSessionRollups,
+		Priority: TaskPriorityLow,
+		Data: map[string]any{
+			"realm": realm,
+		},
+	}
+	// This task does not make any requests to WG
+	return CreateBulkTask(realm, task, splitTasksByTargets(500))
+}
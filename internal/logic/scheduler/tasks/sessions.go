@@ -2,26 +2,36 @@ package tasks
 
 import (
 	"errors"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/cache"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/cufee/am-wg-proxy-next/v2/utils"
 )
 
 func init() {
 	registerTaskHandler(TaskRecordSessions, TaskHandler{
 		Process: func(task *Task) (string, error) {
 			if task.Data == nil {
-				return "no data provided", errors.New("no data provided")
+				return "no data provided", errNoTaskData
 			}
 			realm, ok := task.Data["realm"].(string)
 			if !ok {
-				return "invalid realm", errors.New("invalid realm")
+				return "invalid realm", errInvalidRealm
 			}
 
-			accountErrs, err := cache.RefreshSessionsAndAccounts(models.SessionTypeDaily, nil, realm, task.Targets...)
+			// Tasks created for a custom reset time record sessions with a reference to that reset
+			sessionType, referenceId := models.SessionTypeDaily, (*string)(nil)
+			if reference, ok := task.Data["reference"].(string); ok && reference != "" {
+				sessionType, referenceId = models.SessionTypeReset, &reference
+			}
+
+			accountErrs, err := cache.RefreshSessionsAndAccounts(wargaming.Clients.Cache, sessionType, referenceId, realm, task.Targets...)
 			if err != nil {
 				return "failed to refresh sessions on all account", err
 			}
@@ -40,32 +50,77 @@ func init() {
 			task.Targets = failedAccounts
 			return "retrying failed accounts", errors.New("some accounts failed")
 		},
-		RetryOnFail: func(task *Task) bool {
-			triesLeft, ok := task.Data["triesLeft"].(int32)
-			if !ok {
-				return false
-			}
-			if triesLeft <= 0 {
-				return false
-			}
-
-			triesLeft -= 1
-			task.Data["triesLeft"] = triesLeft
-			task.ScheduledAfter = time.Now().Add(5 * time.Minute) // Backoff for 5 minutes to avoid spamming
-			return true
-		},
 	})
 }
 
 func CreateSessionUpdateTasks(realm string) error {
 	realm = strings.ToUpper(realm)
 	task := Task{
-		Type: TaskRecordSessions,
+		Type:     TaskRecordSessions,
+		Priority: TaskPriorityHigh,
 		Data: map[string]any{
-			"realm":     realm,
-			"triesLeft": int32(3),
+			"realm": realm,
 		},
 	}
 	// This update requires (2 + n) requests per n players
-	return CreateBulkTask(bson.M{"realm": realm}, task, splitTasksByTargets(50))
+	return CreateBulkTask(realm, task, splitTasksByTargets(50))
+}
+
+/*
+CreateSessionResetTasks records sessions and achievements for users with a custom reset time due within the hour starting at hour.
+Accounts are grouped by realm and reset reference, so users sharing a reset time share the snapshots.
+*/
+func CreateSessionResetTasks(hour time.Time) error {
+	users, err := database.FindUsersWithSessionReset()
+	if err != nil {
+		return err
+	}
+
+	type bucket struct {
+		realm     string
+		reference string
+	}
+	buckets := make(map[bucket][]int)
+	for _, user := range users {
+		if user.SessionReset == nil || !user.SessionReset.Due(hour) {
+			continue
+		}
+		for _, connection := range user.ConnectionsByType(models.ConnectionTypeWargaming) {
+			accountId, err := strconv.Atoi(connection.ExternalID)
+			if err != nil {
+				continue
+			}
+			key := bucket{realm: utils.RealmFromPlayerID(accountId), reference: user.SessionReset.Reference()}
+			if !slices.Contains(buckets[key], accountId) {
+				buckets[key] = append(buckets[key], accountId)
+			}
+		}
+	}
+
+	var tasks []Task
+	for key, accounts := range buckets {
+		tasks = append(tasks, splitTasksByTargets(50)(Task{
+			Type:     TaskRecordSessions,
+			Priority: TaskPriorityHigh,
+			Targets:  accounts,
+			Data: map[string]any{
+				"realm":     key.realm,
+				"reference": key.reference,
+			},
+		})...)
+		// Achievements are diffed against a snapshot from the same reset as the session
+		tasks = append(tasks, splitTasksByTargets(100)(Task{
+			Type:     TaskRecordPlayerAchievements,
+			Priority: TaskPriorityLow,
+			Targets:  accounts,
+			Data: map[string]any{
+				"realm":     key.realm,
+				"reference": key.reference,
+			},
+		})...)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	return CreateTasks(tasks...)
 }
@@ -3,13 +3,12 @@ package tasks
 import (
 	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/database"
-	"github.com/rs/zerolog/log"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
@@ -18,15 +17,37 @@ const (
 	TaskRecordSessions           = "RECORD_ACCOUNT_SESSIONS"
 	TaskUpdateAccountWN8         = "UPDATE_ACCOUNT_WN8"
 	TaskRecordPlayerAchievements = "UPDATE_ACCOUNT_ACHIEVEMENTS"
+	TaskRecordRatingSnapshots    = "RECORD_RATING_SNAPSHOTS"
+	TaskCompactSessionRollups    = "COMPACT_SESSION_ROLLUPS"
 )
 
+/*
+Realms with scheduled tasks, tasks are claimed separately for each realm
+*/
+var Realms = []string{"NA", "EU", "AS"}
+
+/*
+TaskLeaseDuration is how long a claimed task is reserved for a worker, leases are renewed while the task is being processed
+*/
+const TaskLeaseDuration = time.Minute * 5
+
+/*
+WorkerID identifies this instance when claiming tasks, it is unique per process
+*/
+var WorkerID = newWorkerID()
+
 var taskHandlers = make(map[string]TaskHandler)
 
 type TaskHandler struct {
-	RetryOnFail func(*Task) bool
+	RetryPolicy *RetryPolicy // DefaultRetryPolicy is used when nil
 	Process     func(*Task) (string, error)
 }
 
+var (
+	errNoTaskData   = Permanent(errors.New("no data provided"))
+	errInvalidRealm = Permanent(errors.New("invalid realm"))
+)
+
 func registerTaskHandler(kind string, handler TaskHandler) {
 	if _, ok := taskHandlers[kind]; ok {
 		panic(fmt.Sprintf("task handler for %s already registered", kind))
@@ -34,71 +55,42 @@ func registerTaskHandler(kind string, handler TaskHandler) {
 	taskHandlers[kind] = handler
 }
 
-// Task statuses
-type taskStatus string
-
-const TaskStatusScheduled taskStatus = "TASK_SCHEDULED"
-const TaskStatusInProgress taskStatus = "TASK_IN_PROGRESS"
-const TaskStatusComplete taskStatus = "TASK_COMPLETE"
-const TaskStatusFailed taskStatus = "TASK_FAILED"
-
-type Task struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty"`
-	Type      string             `bson:"kind"`
-	CreatedAt time.Time          `bson:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at"`
-
-	Targets []int `bson:"targets"`
-
-	Logs []AttemptLog `bson:"logs"`
-
-	Status         taskStatus `bson:"status"`
-	ScheduledAfter time.Time  `bson:"scheduled_after"`
-	LastAttempt    time.Time  `bson:"last_attempt"`
+type Task = models.Task
+type AttemptLog = models.AttemptLog
 
-	Data map[string]any `bson:"data"`
-}
-
-func (t *Task) LogAttempt(log AttemptLog) {
-	t.Logs = append(t.Logs, log)
-}
+const (
+	TaskStatusScheduled  = models.TaskStatusScheduled
+	TaskStatusInProgress = models.TaskStatusInProgress
+	TaskStatusComplete   = models.TaskStatusComplete
+	TaskStatusFailed     = models.TaskStatusFailed
+	TaskStatusDeadLetter = models.TaskStatusDeadLetter
+)
 
-func (t *Task) OnCreated() {
-	t.LastAttempt = time.Now()
-	t.CreatedAt = time.Now()
-	t.UpdatedAt = time.Now()
-}
-func (t *Task) OnUpdated() {
-	t.UpdatedAt = time.Now()
-}
+const (
+	TaskPriorityLow    = models.TaskPriorityLow
+	TaskPriorityNormal = models.TaskPriorityNormal
+	TaskPriorityHigh   = models.TaskPriorityHigh
+)
 
-func (t *Task) RetryOnFail() bool {
-	handlers, ok := taskHandlers[t.Type]
-	if !ok {
-		return false
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "worker"
 	}
-
-	t.LastAttempt = time.Now()
-	return handlers.RetryOnFail(t)
+	return hostname + "-" + primitive.NewObjectID().Hex()
 }
 
-func (t *Task) Process() (string, error) {
+func processTask(t *Task) (string, error) {
 	handlers, ok := taskHandlers[t.Type]
 	if !ok {
 		return "", fmt.Errorf("no handler for task type %s", t.Type)
 	}
 
+	t.Attempts++
 	t.LastAttempt = time.Now()
 	return handlers.Process(t)
 }
 
-type AttemptLog struct {
-	Targets   []int     `json:"targets" bson:"targets"`
-	Timestamp time.Time `json:"timestamp" bson:"timestamp"`
-	Comment   string    `json:"result" bson:"result"`
-	Error     string    `json:"error" bson:"error"`
-}
-
 func NewAttemptLog(task Task, comment, err string) AttemptLog {
 	return AttemptLog{
 		Targets:   task.Targets,
@@ -109,36 +101,28 @@ func NewAttemptLog(task Task, comment, err string) AttemptLog {
 }
 
 /*
-Retrieves all target IDs from the database based on task.Type() and filter, creates a new task in queue.
+Retrieves all target IDs on a realm based on task.Type, creates a new task in queue.
   - If splitTaskFn is provided, it will split the task into subtasks.
 */
-func CreateBulkTask(filter bson.M, task Task, splitTaskFn func(Task) []Task) (err error) {
+func CreateBulkTask(realm string, task Task, splitTaskFn func(Task) []Task) (err error) {
 	if len(task.Targets) != 0 {
 		return errors.New("target IDs already set")
 	}
 
-	ctx, cancel := database.DefaultClient.Ctx()
-	defer cancel()
-
 	switch task.Type {
 	case TaskUpdateClans:
 		// Get all clan IDs on the realm
-		result, err := database.DefaultClient.Collection(database.CollectionClans).Distinct(ctx, "_id", filter)
+		task.Targets, err = database.GetRealmClanIDs(realm)
 		if err != nil {
 			return err
 		}
 
-		var targetIDs []int
-		for _, id := range result {
-			if idInt, ok := id.(int); ok {
-				targetIDs = append(targetIDs, idInt)
-			} else {
-				log.Error().Msgf("invalid clan ID type: %T", id)
-			}
-		}
-
-		task.Targets = targetIDs
-
+	case TaskCompactSessionRollups:
+		// All players on the realm
+		fallthrough
+	case TaskRecordRatingSnapshots:
+		// All players on the realm
+		fallthrough
 	case TaskRecordPlayerAchievements:
 		// All players on the realm
 		fallthrough
@@ -147,36 +131,17 @@ func CreateBulkTask(filter bson.M, task Task, splitTaskFn func(Task) []Task) (er
 		fallthrough
 	case TaskRecordSessions:
 		// Get all player IDs on the realm
-		result, err := database.DefaultClient.Collection(database.CollectionAccounts).Distinct(ctx, "_id", filter)
+		task.Targets, err = database.GetRealmAccountIDs(realm)
 		if err != nil {
 			return err
 		}
 
-		var targetIDs []int
-		for _, id := range result {
-			var idInt int
-			switch cast := id.(type) {
-			case int:
-				idInt = cast
-			case int32:
-				idInt = int(cast)
-			case int64:
-				idInt = int(cast)
-			default:
-				log.Error().Msgf("invalid player ID %v type: %T", cast, id)
-			}
-			if idInt != 0 {
-				targetIDs = append(targetIDs, idInt)
-			}
-		}
-		task.Targets = targetIDs
-
 	default:
 		return errors.New("invalid task type")
 	}
 
 	if len(task.Targets) == 0 {
-		return fmt.Errorf("no targets found for task type %s and filter %+v", task.Type, filter)
+		return fmt.Errorf("no targets found for task type %s on realm %s", task.Type, realm)
 	}
 
 	if splitTaskFn != nil {
@@ -186,102 +151,56 @@ func CreateBulkTask(filter bson.M, task Task, splitTaskFn func(Task) []Task) (er
 }
 
 func CreateTasks(tasks ...Task) error {
-	var writes []mongo.WriteModel
-	for _, task := range tasks {
-		if len(task.Targets) == 0 {
+	for i := range tasks {
+		if len(tasks[i].Targets) == 0 {
 			return errors.New("task targets not set")
 		}
 
-		task.OnCreated()
-		task.Status = TaskStatusScheduled
-		writes = append(writes, mongo.NewInsertOneModel().SetDocument(task))
-	}
-
-	ctx, cancel := database.DefaultClient.Ctx()
-	defer cancel()
-
-	_, err := database.DefaultClient.Collection(database.CollectionTasks).BulkWrite(ctx, writes)
-	if err != nil {
-		return err
+		tasks[i].OnCreated()
+		tasks[i].Status = TaskStatusScheduled
 	}
 
-	return err
+	return database.CreateTasks(tasks...)
 }
 
 func UpdateTasks(tasks ...Task) error {
-	var writes []mongo.WriteModel
-	for _, task := range tasks {
-		if task.ID.IsZero() {
+	for i := range tasks {
+		if tasks[i].ID.IsZero() {
 			return errors.New("task ID not set")
 		}
-		task.OnUpdated()
-		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(bson.M{"_id": task.ID}).SetUpdate(bson.M{"$set": task}))
-	}
-
-	if len(writes) == 0 {
-		return nil
+		tasks[i].OnUpdated()
 	}
 
-	ctx, cancel := database.DefaultClient.Ctx()
-	defer cancel()
-
-	_, err := database.DefaultClient.Collection(database.CollectionTasks).BulkWrite(ctx, writes)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return database.UpdateTasks(tasks...)
 }
 
 /*
-Retrieves all tasks with status TaskStatusScheduled that match filter and updates their status to TaskStatusInProgress.
+Claims up to limitPerRealm tasks with status TaskStatusScheduled for each realm and updates their status to TaskStatusInProgress.
+Claiming per realm keeps a large refresh on one realm from delaying tasks on the others, a final pass with no realm filter picks up tasks without a known realm.
+Tasks claimed before an error are still returned and need to be processed, they are already leased to this worker.
 */
-func StartScheduledTasks(filter bson.M, limit int) ([]Task, error) {
-	if filter == nil {
-		filter = bson.M{}
+func StartScheduledTasks(limitPerRealm int) ([]Task, error) {
+	var claimed []Task
+	var errs []error
+	for _, realm := range Realms {
+		tasks, err := database.StartScheduledTasks(WorkerID, limitPerRealm, TaskLeaseDuration, realm)
+		claimed = append(claimed, tasks...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", realm, err))
+		}
 	}
 
-	ctx, cancel := database.DefaultClient.Ctx()
-	defer cancel()
-
-	var pipeline mongo.Pipeline
-	filter["status"] = TaskStatusScheduled
-	filter["scheduled_after"] = bson.M{"$lte": time.Now()}
-	pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
-	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
-	pipeline = append(pipeline, bson.D{{Key: "$set", Value: bson.M{"status": TaskStatusInProgress}}})
-
-	var tasks []Task
-	cur, err := database.DefaultClient.Collection(database.CollectionTasks).Aggregate(ctx, pipeline)
+	tasks, err := database.StartScheduledTasks(WorkerID, limitPerRealm, TaskLeaseDuration)
+	claimed = append(claimed, tasks...)
 	if err != nil {
-		return nil, err
+		errs = append(errs, err)
 	}
-
-	return tasks, cur.All(ctx, &tasks)
+	return claimed, errors.Join(errs...)
 }
 
 /*
-Retrieves all tasks with status TaskStatusInProgress that match filter and updates their status to TaskStatusScheduled if their last attempt was more than an hour ago.
+Retrieves all tasks with status TaskStatusInProgress and updates their status to TaskStatusScheduled if their lease has expired.
 */
-func RestartAbandonedTasks(filter bson.M) ([]Task, error) {
-	if filter == nil {
-		filter = bson.M{}
-	}
-
-	ctx, cancel := database.DefaultClient.Ctx()
-	defer cancel()
-
-	var pipeline mongo.Pipeline
-	filter["status"] = TaskStatusInProgress
-	filter["last_attempt"] = bson.M{"$lte": time.Now().Add(-time.Hour)}
-	pipeline = append(pipeline, bson.D{{Key: "$match", Value: filter}})
-	pipeline = append(pipeline, bson.D{{Key: "$set", Value: bson.M{"status": TaskStatusScheduled}}})
-
-	var tasks []Task
-	cur, err := database.DefaultClient.Collection(database.CollectionTasks).Aggregate(ctx, pipeline)
-	if err != nil {
-		return nil, err
-	}
-
-	return tasks, cur.All(ctx, &tasks)
+func RestartAbandonedTasks() ([]Task, error) {
+	return database.RestartAbandonedTasks()
 }
@@ -0,0 +1,34 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+)
+
+func TestStartScheduledTasks(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = memory.NewStorage()
+
+	scheduled := time.Now().Add(-time.Minute)
+	err := database.CreateTasks(
+		Task{Type: TaskUpdateClans, Status: TaskStatusScheduled, ScheduledAfter: scheduled, Data: map[string]any{"realm": "NA"}},
+		Task{Type: TaskUpdateClans, Status: TaskStatusScheduled, ScheduledAfter: scheduled, Data: map[string]any{"realm": "RU"}},
+		Task{Type: TaskUpdateClans, Status: TaskStatusScheduled, ScheduledAfter: scheduled},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tasks on unknown realms or without a realm are claimed in the final pass
+	claimed, err := StartScheduledTasks(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claimed) != 3 {
+		t.Errorf("expected 3 claimed tasks, got %d", len(claimed))
+	}
+}
@@ -9,7 +9,7 @@ func splitTasksByTargets(batchSize int) func(task Task) []Task {
 		var tasks []Task
 		subTasks := len(task.Targets) / batchSize
 
-		for i := 0; i <= subTasks; i++ {
+		for i := 0; i <= subTasks && batchSize*i < len(task.Targets); i++ {
 			subTask := task
 			if len(task.Targets) > batchSize*(i+1) {
 				subTask.Targets = (task.Targets[batchSize*i : batchSize*(i+1)])
@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/cache"
+)
+
+func init() {
+	registerTaskHandler(TaskUpdateAccountWN8, TaskHandler{
+		Process: func(task *Task) (string, error) {
+			if task.Data == nil {
+				return "no data provided", errNoTaskData
+			}
+			realm, ok := task.Data["realm"].(string)
+			if !ok {
+				return "invalid realm", errInvalidRealm
+			}
+
+			accountErrs, err := cache.UpdateAccountsWN8(wargaming.Clients.Cache, realm, task.Targets...)
+			if err != nil {
+				return "failed to update WN8 on all accounts", err
+			}
+
+			var failedAccounts []int
+			for accountId, err := range accountErrs {
+				if err != nil && accountId != 0 {
+					failedAccounts = append(failedAccounts, accountId)
+				}
+			}
+			if len(failedAccounts) == 0 {
+				return "finished WN8 update on all accounts", nil
+			}
+
+			// Retry failed accounts
+			task.Targets = failedAccounts
+			return "retrying failed accounts", errors.New("some accounts failed")
+		},
+	})
+}
+
+func CreateAccountWN8UpdateTasks(realm string) error {
+	realm = strings.ToUpper(realm)
+	task := Task{
+		Type:     TaskUpdateAccountWN8,
+		Priority: TaskPriorityLow,
+		Data: map[string]any{
+			"realm": realm,
+		},
+	}
+	// This update requires (2 + n) requests per n players
+	return CreateBulkTask(realm, task, splitTasksByTargets(50))
+}
@@ -1,9 +1,13 @@
 package scheduler
 
 import (
+	"time"
+
 	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/cache"
 	"github.com/cufee/aftermath-core/internal/logic/content"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
 	"github.com/cufee/aftermath-core/internal/logic/scheduler/tasks"
 	"github.com/rs/zerolog/log"
 )
@@ -25,7 +29,7 @@ func rotateBackgroundPresetsWorker() {
 func updateGlossaryWorker() {
 	// We just run the logic directly as it's not a heavy task and it doesn't matter if it fails due to the app failing
 	log.Info().Msg("updating glossary cache")
-	err := cache.UpdateGlossaryCache()
+	err := cache.UpdateGlossaryCache(wargaming.Clients.Cache)
 	if err != nil {
 		log.Err(err).Msg("failed to update glossary cache")
 	} else {
@@ -33,6 +37,17 @@ func updateGlossaryWorker() {
 	}
 }
 
+func updateAchievementsWorker() {
+	// We just run the logic directly as it's not a heavy task and it doesn't matter if it fails due to the app failing
+	log.Info().Msg("updating achievements glossary cache")
+	err := cache.UpdateAchievementsGlossaryCache()
+	if err != nil {
+		log.Err(err).Msg("failed to update achievements glossary cache")
+	} else {
+		log.Info().Msg("achievements glossary cache updated")
+	}
+}
+
 func updateAveragesWorker() {
 	// We just run the logic directly as it's not a heavy task and it doesn't matter if it fails due to the app failing
 	log.Info().Msg("updating averages cache")
@@ -53,15 +68,89 @@ func createSessionTasksWorker(realm string) func() {
 	}
 }
 
+func createSessionResetTasksWorker() {
+	err := tasks.CreateSessionResetTasks(time.Now())
+	if err != nil {
+		log.Err(err).Msg("failed to create session reset tasks")
+	}
+}
+
+func createClanTasksWorker(realm string) func() {
+	return func() {
+		err := tasks.CreateClanUpdateTasks(realm)
+		if err != nil {
+			log.Err(err).Msg("failed to create clan update tasks")
+		}
+	}
+}
+
+func createWN8TasksWorker(realm string) func() {
+	return func() {
+		err := tasks.CreateAccountWN8UpdateTasks(realm)
+		if err != nil {
+			log.Err(err).Msg("failed to create WN8 update tasks")
+		}
+	}
+}
+
+func createRollupTasksWorker(realm string) func() {
+	return func() {
+		err := tasks.CreateSessionRollupTasks(realm)
+		if err != nil {
+			log.Err(err).Msg("failed to create session rollup tasks")
+		}
+	}
+}
+
+func createAchievementsTasksWorker(realm string) func() {
+	return func() {
+		err := tasks.CreateAchievementsSnapshotTasks(realm)
+		if err != nil {
+			log.Err(err).Msg("failed to create achievements snapshot tasks")
+		}
+	}
+}
+
+func ratingSeasonWorker(realm string) func() {
+	return func() {
+		season, err := wotblitz.GetCurrentRatingSeason(realm)
+		if err != nil {
+			log.Err(err).Msg("failed to get current rating season")
+			return
+		}
+
+		closed, err := database.CloseRatingSeasons(realm, season.SeasonID)
+		if err != nil {
+			log.Err(err).Msg("failed to close rating seasons")
+		} else if closed > 0 {
+			log.Info().Msgf("closed %d rating season snapshots on %s", closed, realm)
+		}
+
+		err = tasks.CreateRatingSnapshotTasks(realm, time.Now())
+		if err != nil {
+			log.Err(err).Msg("failed to create rating snapshot tasks")
+		}
+
+		// Record one more snapshot right before the season ends, this will become the final snapshot
+		finish := season.Finish()
+		if untilFinish := time.Until(finish); untilFinish > time.Hour && untilFinish < time.Hour*24 {
+			err = tasks.CreateRatingSnapshotTasks(realm, finish.Add(-time.Hour))
+			if err != nil {
+				log.Err(err).Msg("failed to create final rating snapshot tasks")
+			}
+		}
+	}
+}
+
 func runTasksWorker() {
 	if tasks.DefaultQueue.ActiveWorkers() > 0 {
 		return
 	}
 
-	activeTasks, err := tasks.StartScheduledTasks(nil, 50)
+	// Tasks that were claimed before an error are leased to this worker and need to be processed
+	activeTasks, err := tasks.StartScheduledTasks(20)
 	if err != nil {
-		log.Err(err).Msg("failed to start scheduled tasks")
-		return
+		log.Err(err).Int("claimed", len(activeTasks)).Msg("failed to start some scheduled tasks")
 	}
 	if len(activeTasks) == 0 {
 		return
@@ -80,7 +169,7 @@ func runTasksWorker() {
 }
 
 func restartTasksWorker() {
-	_, err := tasks.RestartAbandonedTasks(nil)
+	_, err := tasks.RestartAbandonedTasks()
 	if err != nil {
 		log.Err(err).Msg("failed to start scheduled tasks")
 		return
@@ -2,7 +2,7 @@ package accounts
 
 import (
 	"github.com/cufee/aftermath-core/internal/core/server"
-	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -13,9 +13,9 @@ func SearchAccountsHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponse("realm and search query parameters are required", "c.QueryParam"))
 	}
 
-	accounts, err := wargaming.Clients.Live.SearchAccounts(realm, searchQuery)
+	accounts, err := middleware.Providers(c).Live.SearchAccounts(realm, searchQuery)
 	if err != nil {
-		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "wargaming.Clients.Live.SearchAccounts"))
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "middleware.Providers.Live.SearchAccounts"))
 	}
 
 	return c.JSON(server.NewResponse(accounts))
@@ -0,0 +1,41 @@
+package accounts
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AccountWN8Response struct {
+	AccountID int                 `json:"accountId"`
+	Current   models.AccountWN8   `json:"current"`
+	History   []models.AccountWN8 `json:"history"`
+}
+
+func GetAccountWN8Handler(c *fiber.Ctx) error {
+	accountId, err := strconv.Atoi(c.Params("account"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
+
+	account, err := database.GetPlayerAccount(accountId)
+	if err != nil {
+		if errors.Is(err, database.ErrAccountNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetPlayerAccount"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetPlayerAccount"))
+	}
+	if account.WN8 == nil {
+		return c.Status(404).JSON(server.NewErrorResponse("career wn8 was not calculated for this account yet", "account.WN8"))
+	}
+
+	return c.JSON(server.NewResponse(AccountWN8Response{
+		AccountID: account.ID,
+		Current:   *account.WN8,
+		History:   account.WN8History,
+	}))
+}
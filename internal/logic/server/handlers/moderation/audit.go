@@ -0,0 +1,78 @@
+package moderation
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	auditLogDefaultLimit = 100
+	auditLogMaxLimit     = 1000
+)
+
+func GetAuditLogHandler(c *fiber.Ctx) error {
+	query := models.AuditLogQuery{
+		ActorID:      c.Query("actor"),
+		TargetUserID: c.Query("user"),
+		Limit:        auditLogDefaultLimit,
+	}
+
+	var err error
+	if from := c.Query("from"); from != "" {
+		query.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return c.Status(400).JSON(server.NewErrorResponseFromError(err, "time.Parse"))
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		query.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return c.Status(400).JSON(server.NewErrorResponseFromError(err, "time.Parse"))
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 {
+			return c.Status(400).JSON(server.NewErrorResponse("invalid limit", "strconv.Atoi"))
+		}
+		query.Limit = min(query.Limit, auditLogMaxLimit)
+	}
+
+	entries, err := database.FindAuditLogEntries(query)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindAuditLogEntries"))
+	}
+
+	return c.JSON(server.NewResponse(entries))
+}
+
+/*
+recordAuditEntry saves a moderation action performed by the authenticated actor, the request already succeeded at this point so errors are only logged
+*/
+func recordAuditEntry(c *fiber.Ctx, action models.AuditAction, targetUserID, targetID string, before, after any) {
+	actor, _ := middleware.Actor(c)
+
+	changes, err := models.NewAuditChanges(before, after)
+	if err != nil {
+		log.Err(err).Str("action", string(action)).Msg("failed to diff audit log values")
+	}
+
+	err = database.AddAuditLogEntries(models.AuditLogEntry{
+		CreatedAt:    time.Now(),
+		ActorID:      actor.ID,
+		Action:       action,
+		TargetUserID: targetUserID,
+		TargetID:     targetID,
+		Changes:      changes,
+	})
+	if err != nil {
+		log.Err(err).Str("action", string(action)).Str("actor", actor.ID).Msg("failed to save audit log entry")
+	}
+}
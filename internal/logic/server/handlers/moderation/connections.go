@@ -6,6 +6,7 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/internal/logic/server/handlers/users"
 	"github.com/cufee/aftermath-core/types"
 	"github.com/gofiber/fiber/v2"
 )
@@ -29,11 +30,19 @@ func ForceUpdateConnectionHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "users.GetOrCreateUserByID"))
 	}
 
+	var before any
+	existing, err := database.GetUserConnectionByExternalID(user.ID, connectionType, opts.ConnectionID)
+	if err != nil && !errors.Is(err, database.ErrConnectionNotFound) {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserConnectionByExternalID"))
+	}
+	if err == nil {
+		before = existing
+	}
+
 	var update models.ConnectionUpdate
 	update.Metadata = opts.Metadata
-	update.ExternalID = &opts.ConnectionID
 
-	connection, err := database.UpdateUserConnection(user.ID, connectionType, update)
+	connection, err := database.UpdateUserConnection(user.ID, connectionType, opts.ConnectionID, update)
 	if err != nil {
 		if !errors.Is(err, database.ErrConnectionNotFound) {
 			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnection"))
@@ -44,5 +53,17 @@ func ForceUpdateConnectionHandler(c *fiber.Ctx) error {
 		}
 	}
 
+	recordAuditEntry(c, models.AuditActionConnectionForceUpdate, user.ID, connection.ID.Hex(), before, connection)
 	return c.JSON(server.NewResponse(connection))
 }
+
+var GetUserConnectionsHandler = users.NewGetConnectionsHandler("userId")
+
+/*
+RemoveUserConnectionHandler removes all connections of a type when no account is provided, every removed connection is audited
+*/
+var RemoveUserConnectionHandler = users.NewRemoveConnectionsHandler("userId", false, func(c *fiber.Ctx, userId string, removed []models.UserConnection) {
+	for _, connection := range removed {
+		recordAuditEntry(c, models.AuditActionConnectionRemove, userId, connection.ID.Hex(), connection, nil)
+	}
+})
@@ -1,8 +1,11 @@
 package moderation
 
 import (
+	"errors"
+
 	"github.com/cufee/aftermath-core/internal/core/cloudinary"
 	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	"github.com/cufee/aftermath-core/internal/logic/content"
 	"github.com/cufee/aftermath-core/types"
@@ -24,15 +27,21 @@ func UploadBackgroundImageHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "content.EncodeRemoteImage"))
 	}
 
-	link, err := cloudinary.DefaultClient.ManualUpload(image)
+	link, err := cloudinary.DefaultClient().ManualUpload(image)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "content.UploadUserImage"))
 	}
 
+	recordAuditEntry(c, models.AuditActionContentUpload, "", link, nil, link)
 	return c.JSON(server.NewResponse(link))
 }
 
 func RotateBackgroundImagesHandler(c *fiber.Ctx) error {
+	before, err := database.GetAppConfiguration[[]string]("backgroundImagesSelection")
+	if err != nil && !errors.Is(err, database.ErrConfigurationNotFound) {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetAppConfiguration"))
+	}
+
 	images, err := content.PickRandomBackgroundImages(3)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "content.PickRandomBackgroundImages"))
@@ -42,5 +51,7 @@ func RotateBackgroundImagesHandler(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateAppConfiguration"))
 	}
+
+	recordAuditEntry(c, models.AuditActionContentRotate, "", "backgroundImagesSelection", before.Value, images)
 	return c.JSON(server.NewResponse(images))
 }
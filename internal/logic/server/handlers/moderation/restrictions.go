@@ -0,0 +1,127 @@
+package moderation
+
+import (
+	"errors"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
+	"github.com/cufee/aftermath-core/types"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetUserRestrictionsHandler(c *fiber.Ctx) error {
+	userId := c.Params("userId")
+	if userId == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("user id required", ""))
+	}
+
+	restrictions, err := database.FindUserRestrictions(userId)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserRestrictions"))
+	}
+
+	return c.JSON(server.NewResponse(restrictions))
+}
+
+func GetRestrictionHandler(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "primitive.ObjectIDFromHex"))
+	}
+
+	restriction, err := database.GetUserRestriction(id)
+	if err != nil {
+		if errors.Is(err, database.ErrRestrictionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetUserRestriction"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserRestriction"))
+	}
+
+	return c.JSON(server.NewResponse(restriction))
+}
+
+func CreateRestrictionHandler(c *fiber.Ctx) error {
+	var body types.RestrictionPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
+	}
+
+	actor, _ := middleware.Actor(c)
+	payload, valid := body.ToUserRestriction(actor.ID)
+	if !valid {
+		return c.Status(400).JSON(server.NewErrorResponse("invalid restriction payload", ""))
+	}
+
+	restriction, err := database.AddUserRestriction(payload)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.AddUserRestriction"))
+	}
+
+	recordAuditEntry(c, models.AuditActionRestrictionCreate, restriction.UserID, restriction.ID.Hex(), nil, restriction)
+	return c.JSON(server.NewResponse(restriction))
+}
+
+func UpdateRestrictionHandler(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "primitive.ObjectIDFromHex"))
+	}
+
+	var body types.RestrictionPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
+	}
+
+	update, valid := body.ToRestrictionUpdate()
+	if !valid {
+		return c.Status(400).JSON(server.NewErrorResponse("invalid restriction scopes", ""))
+	}
+
+	before, err := database.GetUserRestriction(id)
+	if err != nil {
+		if errors.Is(err, database.ErrRestrictionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetUserRestriction"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserRestriction"))
+	}
+
+	restriction, err := database.UpdateUserRestriction(id, update)
+	if err != nil {
+		if errors.Is(err, database.ErrRestrictionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserRestriction"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserRestriction"))
+	}
+
+	recordAuditEntry(c, models.AuditActionRestrictionUpdate, restriction.UserID, restriction.ID.Hex(), before, restriction)
+	return c.JSON(server.NewResponse(restriction))
+}
+
+func DeleteRestrictionHandler(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "primitive.ObjectIDFromHex"))
+	}
+
+	before, err := database.GetUserRestriction(id)
+	if err != nil {
+		if errors.Is(err, database.ErrRestrictionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetUserRestriction"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserRestriction"))
+	}
+
+	err = database.DeleteUserRestriction(id)
+	if err != nil {
+		if errors.Is(err, database.ErrRestrictionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.DeleteUserRestriction"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.DeleteUserRestriction"))
+	}
+
+	recordAuditEntry(c, models.AuditActionRestrictionDelete, before.UserID, before.ID.Hex(), before, nil)
+	return c.JSON(server.NewResponse(id))
+}
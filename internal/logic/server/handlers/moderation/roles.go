@@ -0,0 +1,63 @@
+package moderation
+
+import (
+	"errors"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/permissions/v2"
+	"github.com/cufee/aftermath-core/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetUserPermissionsHandler(c *fiber.Ctx) error {
+	userId := c.Params("userId")
+	if userId == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("user id required", ""))
+	}
+
+	user, err := database.GetUserByID(userId)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetUserByID"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserByID"))
+	}
+
+	return c.JSON(server.NewResponse(types.NewUserPermissions(user)))
+}
+
+func UpdateUserPermissionsHandler(c *fiber.Ctx) error {
+	userId := c.Params("userId")
+	if userId == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("user id required", ""))
+	}
+
+	var body types.UserPermissionsPayload
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
+	}
+
+	user, err := database.GetOrCreateUserByID(userId)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetOrCreateUserByID"))
+	}
+
+	before := types.NewUserPermissions(user)
+
+	updated, err := body.Apply(permissions.Parse(user.User.Permissions))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "payload"))
+	}
+
+	user.User.Permissions = updated.Encode()
+	user.User, err = database.UpdateUser(user.ID, user.User)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateUser"))
+	}
+
+	after := types.NewUserPermissions(user)
+	recordAuditEntry(c, models.AuditActionPermissionsUpdate, user.ID, user.ID, before, after)
+	return c.JSON(server.NewResponse(after))
+}
@@ -0,0 +1,29 @@
+package moderation
+
+import (
+	"errors"
+
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/internal/logic/scheduler"
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetSchedulesHandler(c *fiber.Ctx) error {
+	return c.JSON(server.NewResponse(scheduler.Jobs()))
+}
+
+func RunScheduledJobHandler(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	err := scheduler.RunJob(name)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrUnknownJob) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "scheduler.RunJob"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "scheduler.RunJob"))
+	}
+
+	recordAuditEntry(c, models.AuditActionScheduleRun, "", name, nil, nil)
+	return c.JSON(server.NewResponse(name))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	"github.com/cufee/aftermath-core/types"
 	"github.com/gofiber/fiber/v2"
@@ -66,11 +67,20 @@ func UpdateSubscriptionHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponse("invalid subscription type", ""))
 	}
 
+	before, err := database.GetSubscriptionByID(id)
+	if err != nil {
+		if errors.Is(err, database.ErrSubscriptionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetSubscriptionByID"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetSubscriptionByID"))
+	}
+
 	subscription, err := database.UpdateUserSubscription(id, body.ToSubscriptionUpdate())
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindSubscriptionsByUserID"))
 	}
 
+	recordAuditEntry(c, models.AuditActionSubscriptionUpdate, subscription.UserID, subscription.ID.Hex(), before, subscription)
 	return c.JSON(server.NewResponse(subscription))
 }
 
@@ -95,5 +105,6 @@ func CreateUserSubscriptionsHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindSubscriptionsByUserID"))
 	}
 
+	recordAuditEntry(c, models.AuditActionSubscriptionCreate, subscription.UserID, subscription.ID.Hex(), nil, subscription)
 	return c.JSON(server.NewResponse(subscription))
 }
@@ -0,0 +1,192 @@
+package moderation
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/internal/logic/scheduler/tasks"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	tasksDefaultLimit = 50
+	tasksMaxLimit     = 500
+
+	taskCountsDefaultHours = 24
+	taskCountsMaxHours     = 24 * 7 // tasks expire after a week
+)
+
+func GetTasksHandler(c *fiber.Ctx) error {
+	query := models.TaskQuery{
+		Kind:  c.Query("kind"),
+		Limit: tasksDefaultLimit,
+	}
+
+	var err error
+	if status := c.Query("status"); status != "" {
+		var valid bool
+		query.Status, valid = models.ParseTaskStatus(status)
+		if !valid {
+			return c.Status(400).JSON(server.NewErrorResponse("invalid status", "models.ParseTaskStatus"))
+		}
+	}
+	if from := c.Query("from"); from != "" {
+		query.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return c.Status(400).JSON(server.NewErrorResponseFromError(err, "time.Parse"))
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		query.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return c.Status(400).JSON(server.NewErrorResponseFromError(err, "time.Parse"))
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		query.Limit, err = strconv.Atoi(limit)
+		if err != nil || query.Limit < 1 {
+			return c.Status(400).JSON(server.NewErrorResponse("invalid limit", "strconv.Atoi"))
+		}
+		query.Limit = min(query.Limit, tasksMaxLimit)
+	}
+
+	found, err := database.FindTasks(query)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindTasks"))
+	}
+
+	return c.JSON(server.NewResponse(found))
+}
+
+func GetTaskHandler(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "primitive.ObjectIDFromHex"))
+	}
+
+	task, err := database.GetTaskByID(id)
+	if err != nil {
+		if errors.Is(err, database.ErrTaskNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetTaskByID"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetTaskByID"))
+	}
+
+	return c.JSON(server.NewResponse(task))
+}
+
+func GetTaskLogsHandler(c *fiber.Ctx) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "primitive.ObjectIDFromHex"))
+	}
+
+	task, err := database.GetTaskByID(id)
+	if err != nil {
+		if errors.Is(err, database.ErrTaskNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetTaskByID"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetTaskByID"))
+	}
+
+	return c.JSON(server.NewResponse(task.Logs))
+}
+
+/*
+RetryTaskHandler reschedules a task, tasks that are in progress are rejected so that a second worker cannot claim them while the first one is still running
+*/
+func RetryTaskHandler(c *fiber.Ctx) error {
+	return setTaskStatus(c, models.TaskStatusScheduled, models.AuditActionTaskRetry, models.TaskStatusScheduled, models.TaskStatusInProgress)
+}
+
+func CancelTaskHandler(c *fiber.Ctx) error {
+	return setTaskStatus(c, models.TaskStatusCancelled, models.AuditActionTaskCancel, models.TaskStatusComplete, models.TaskStatusCancelled)
+}
+
+/*
+setTaskStatus updates the status of a task from the id path parameter, tasks currently in one of the skip statuses are rejected with a conflict.
+The update only applies if the status did not change since it was checked.
+*/
+func setTaskStatus(c *fiber.Ctx, status models.TaskStatus, action models.AuditAction, skip ...models.TaskStatus) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "primitive.ObjectIDFromHex"))
+	}
+
+	before, err := database.GetTaskByID(id)
+	if err != nil {
+		if errors.Is(err, database.ErrTaskNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.GetTaskByID"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetTaskByID"))
+	}
+	for _, s := range skip {
+		if before.Status == s {
+			return c.Status(409).JSON(server.NewErrorResponse("task status is "+string(before.Status), "setTaskStatus"))
+		}
+	}
+
+	task, err := database.SetTaskStatus(id, before.Status, status)
+	if err != nil {
+		if errors.Is(err, database.ErrTaskStatusChanged) {
+			return c.Status(409).JSON(server.NewErrorResponseFromError(err, "database.SetTaskStatus"))
+		}
+		if errors.Is(err, database.ErrTaskNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.SetTaskStatus"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.SetTaskStatus"))
+	}
+
+	recordAuditEntry(c, action, "", task.ID.Hex(), taskAuditFields(before), taskAuditFields(task))
+	return c.JSON(server.NewResponse(task))
+}
+
+/*
+taskAuditFields omits targets and logs from a task, these do not change manually and can be very large
+*/
+func taskAuditFields(task models.Task) map[string]any {
+	return map[string]any{
+		"status":         task.Status,
+		"scheduledAfter": task.ScheduledAfter,
+		"workerId":       task.WorkerID,
+	}
+}
+
+func CreateSessionTasksHandler(c *fiber.Ctx) error {
+	realm := c.Params("realm")
+	if realm == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("realm path parameter is required", "c.Param"))
+	}
+
+	err := tasks.CreateSessionUpdateTasks(realm)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "tasks.CreateSessionUpdateTasks"))
+	}
+
+	recordAuditEntry(c, models.AuditActionTaskCreate, "", tasks.TaskRecordSessions, nil, map[string]any{"realm": realm})
+	return c.JSON(server.NewResponse(realm))
+}
+
+func GetTaskCountsHandler(c *fiber.Ctx) error {
+	hours := taskCountsDefaultHours
+	if value := c.Query("hours"); value != "" {
+		var err error
+		hours, err = strconv.Atoi(value)
+		if err != nil || hours < 1 {
+			return c.Status(400).JSON(server.NewErrorResponse("invalid hours", "strconv.Atoi"))
+		}
+		hours = min(hours, taskCountsMaxHours)
+	}
+
+	counts, err := database.CountTasks(time.Now().Add(-time.Hour * time.Duration(hours)))
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.CountTasks"))
+	}
+
+	return c.JSON(server.NewResponse(counts))
+}
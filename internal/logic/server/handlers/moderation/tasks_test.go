@@ -0,0 +1,61 @@
+package moderation
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRetryTask(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+
+	storage := memory.NewStorage()
+	database.DefaultStorage = storage
+
+	err := storage.CreateTasks(models.Task{Type: "a", Status: models.TaskStatusScheduled, ScheduledAfter: time.Now(), CreatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := storage.StartScheduledTasks("worker", 1, time.Minute)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("expected 1 claimed task, got %d %v", len(claimed), err)
+	}
+
+	app := fiber.New()
+	app.Post("/tasks/:id/retry", RetryTaskHandler)
+	app.Post("/tasks/:id/cancel", CancelTaskHandler)
+
+	// Tasks held by a worker cannot be retried, but can be cancelled and retried after
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"/tasks/" + claimed[0].ID.Hex() + "/retry", 409},
+		{"/tasks/" + claimed[0].ID.Hex() + "/cancel", 200},
+		{"/tasks/" + claimed[0].ID.Hex() + "/retry", 200},
+		{"/tasks/" + claimed[0].ID.Hex() + "/retry", 409},
+	}
+	for _, tc := range cases {
+		res, err := app.Test(httptest.NewRequest("POST", tc.path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.path, tc.status, res.StatusCode)
+		}
+	}
+
+	task, err := storage.GetTaskByID(claimed[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Status != models.TaskStatusScheduled || task.WorkerID != "" {
+		t.Errorf("unexpected task %+v", task)
+	}
+}
@@ -15,10 +15,12 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	core "github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/content"
 	renderCore "github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/render/assets"
 	render "github.com/cufee/aftermath-core/internal/logic/render/period"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
 	"github.com/cufee/aftermath-core/internal/logic/stats/period"
 	"github.com/cufee/aftermath-core/types"
 	"github.com/gofiber/fiber/v2"
@@ -38,7 +40,8 @@ func PeriodFromIDHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	imageData, err := getEncodedPeriodImage(accountId, opts)
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	imageData, err := getEncodedPeriodImage(middleware.Providers(c).Live, accountId, opts, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getEncodedSessionImage"))
 	}
@@ -58,12 +61,12 @@ func PeriodFromUserHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	connection, err := database.FindUserConnection(user, models.ConnectionTypeWargaming)
+	connection, err := database.FindUserConnectionOrDefault(user, models.ConnectionTypeWargaming, c.Query("account"))
 	if err != nil {
 		if errors.Is(err, database.ErrConnectionNotFound) {
-			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "models.FindUserConnection"))
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnectionOrDefault"))
 		}
-		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "models.FindUserConnection"))
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnectionOrDefault"))
 	}
 
 	accountId, err := strconv.Atoi(connection.ExternalID)
@@ -71,7 +74,8 @@ func PeriodFromUserHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(server.NewErrorResponse("invalid connection", "strconv.Atoi"))
 	}
 
-	imageData, err := getEncodedPeriodImage(accountId, opts)
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	imageData, err := getEncodedPeriodImage(middleware.Providers(c).Live, accountId, opts, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getEncodedSessionImage"))
 	}
@@ -79,8 +83,8 @@ func PeriodFromUserHandler(c *fiber.Ctx) error {
 	return c.JSON(server.NewResponse(imageData))
 }
 
-func getEncodedPeriodImage(accountId int, options types.PeriodRequestPayload) (string, error) {
-	stats, err := period.GetPlayerStats(accountId, options.Days)
+func getEncodedPeriodImage(client wargaming.StatsProvider, accountId int, options types.PeriodRequestPayload, locale language.Tag) (string, error) {
+	stats, err := period.GetPlayerStats(client, accountId, options.Days)
 	if err != nil {
 		return "", err
 	}
@@ -159,15 +163,15 @@ func getEncodedPeriodImage(accountId int, options types.PeriodRequestPayload) (s
 				VehicleGlossary: vehiclesGlossary,
 			}, dataprep.ExportOptions{
 				Blocks:        dataprep.DefaultBlocks,
-				Locale:        language.English,
-				LocalePrinter: localization.GetPrinter(language.English),
+				Locale:        locale,
+				LocalePrinter: localization.GetPrinter(locale),
 				Highlights:    dataprep.DefaultHighlights,
 			})
 		if err != nil {
 			cardsChan <- core.DataWithError[image.Image]{Err: err}
 		}
 
-		renderOptions := render.RenderOptions{}
+		renderOptions := render.RenderOptions{Locale: locale}
 
 		img, err := render.RenderImage(render.PlayerData{
 			Stats:         stats,
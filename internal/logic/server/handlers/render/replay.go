@@ -33,7 +33,8 @@ func ReplayFromPayload(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponse("url is required", "payload"))
 	}
 
-	imageData, err := getEncodedReplayImage(opts)
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	imageData, err := getEncodedReplayImage(opts, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getEncodedSessionImage"))
 	}
@@ -41,7 +42,7 @@ func ReplayFromPayload(c *fiber.Ctx) error {
 	return c.JSON(server.NewResponse(imageData))
 }
 
-func getEncodedReplayImage(options types.ReplayRequestPayload) (string, error) {
+func getEncodedReplayImage(options types.ReplayRequestPayload, locale language.Tag) (string, error) {
 	unpacked, err := parse.UnpackRemote(options.URL)
 	if err != nil {
 		return "", err
@@ -109,8 +110,8 @@ func getEncodedReplayImage(options types.ReplayRequestPayload) (string, error) {
 			VehicleGlossary:       vehiclesGlossary,
 			Replay:                replay,
 		}, replays.ExportOptions{
-			Locale:        language.English,
-			LocalePrinter: localization.GetPrinter(language.English),
+			Locale:        locale,
+			LocalePrinter: localization.GetPrinter(locale),
 			Blocks:        []dataprep.Tag{dataprep.TagWN8, dataprep.TagDamageDealt, dataprep.TagDamageAssistedCombined, dataprep.TagFrags},
 		})
 		if err != nil {
@@ -118,7 +119,7 @@ func getEncodedReplayImage(options types.ReplayRequestPayload) (string, error) {
 			return
 		}
 
-		img, err := render.RenderReplayImage(render.ReplayData{Cards: cards, Replay: replay}, render.RenderOptions{})
+		img, err := render.RenderReplayImage(render.ReplayData{Cards: cards, Replay: replay}, render.RenderOptions{Locale: locale})
 		cardsChan <- core.DataWithError[image.Image]{Data: img, Err: err}
 	}()
 
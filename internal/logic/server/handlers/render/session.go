@@ -14,12 +14,16 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	core "github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/cache"
 	"github.com/cufee/aftermath-core/internal/logic/content"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
 	renderCore "github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/render/assets"
 	render "github.com/cufee/aftermath-core/internal/logic/render/session"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
 	"github.com/cufee/aftermath-core/internal/logic/stats"
+	"github.com/cufee/aftermath-core/internal/logic/stats/rating"
 	"github.com/cufee/aftermath-core/internal/logic/stats/sessions"
 	"github.com/cufee/aftermath-core/types"
 	"github.com/cufee/am-wg-proxy-next/v2/utils"
@@ -42,7 +46,8 @@ func SessionFromIDHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	imageData, err := getEncodedSessionImage(accountId, opts)
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	imageData, err := getEncodedSessionImage(middleware.Providers(c), accountId, opts, nil, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getEncodedSessionImage"))
 	}
@@ -62,12 +67,12 @@ func SessionFromUserHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	connection, err := database.FindUserConnection(user, models.ConnectionTypeWargaming)
+	connection, err := database.FindUserConnectionOrDefault(user, models.ConnectionTypeWargaming, c.Query("account"))
 	if err != nil {
 		if errors.Is(err, database.ErrConnectionNotFound) {
-			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "models.FindUserConnection"))
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnectionOrDefault"))
 		}
-		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "models.FindUserConnection"))
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnectionOrDefault"))
 	}
 
 	accountId, err := strconv.Atoi(connection.ExternalID)
@@ -75,7 +80,16 @@ func SessionFromUserHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(server.NewErrorResponse("invalid connection", "strconv.Atoi"))
 	}
 
-	imageData, err := getEncodedSessionImage(accountId, opts)
+	// Users without a custom session reset use the realm-wide reset
+	var reset *models.SessionReset
+	if userData, err := database.GetUserByID(user); err == nil {
+		reset = userData.SessionReset
+	} else if !errors.Is(err, database.ErrUserNotFound) {
+		log.Warn().Err(err).Msg("failed to get user session reset")
+	}
+
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	imageData, err := getEncodedSessionImage(middleware.Providers(c), accountId, opts, reset, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getEncodedSessionImage"))
 	}
@@ -83,7 +97,7 @@ func SessionFromUserHandler(c *fiber.Ctx) error {
 	return c.JSON(server.NewResponse(imageData))
 }
 
-func getEncodedSessionImage(accountId int, options types.SessionRequestPayload) (string, error) {
+func getEncodedSessionImage(providers wargaming.Providers, accountId int, options types.SessionRequestPayload, reset *models.SessionReset, locale language.Tag) (string, error) {
 	realm := utils.RealmFromPlayerID(accountId)
 
 	blocks, err := dataprep.ParseTags(options.Presets...)
@@ -91,14 +105,14 @@ func getEncodedSessionImage(accountId int, options types.SessionRequestPayload)
 		blocks = session.DefaultSessionBlocks
 	}
 
-	sessionData, err := sessions.GetCurrentPlayerSession(accountId, database.SessionGetOptions{Type: options.Type(), ReferenceID: options.ReferenceID})
+	sessionData, err := sessions.GetCurrentPlayerSession(providers.Live, accountId, sessions.WithSessionReset(database.SessionGetOptions{Type: options.Type(), ReferenceID: options.ReferenceID}, reset))
 	if err != nil {
 		if !errors.Is(err, sessions.ErrNoSessionCached) {
 			return "", err
 		}
 		// Refresh the session cache in the background
 		go func(realm string, accountId int) {
-			accountErrs, err := cache.RefreshSessionsAndAccounts(options.Type(), options.ReferenceID, realm, accountId)
+			accountErrs, err := cache.RefreshSessionsAndAccounts(providers.Cache, options.Type(), options.ReferenceID, realm, accountId)
 			if err != nil || len(accountErrs) > 0 {
 				log.Err(err).Msg("failed to refresh session cache")
 			}
@@ -107,7 +121,7 @@ func getEncodedSessionImage(accountId int, options types.SessionRequestPayload)
 
 	if sessionData.Account.ClanID != 0 {
 		go func() {
-			err := cache.CacheAllNewClanMembers(realm, sessionData.Account.ClanID)
+			err := cache.CacheAllNewClanMembers(providers.Cache, realm, sessionData.Account.ClanID)
 			if err != nil {
 				log.Err(err).Msg("failed to cache new clan members")
 			}
@@ -207,6 +221,16 @@ func getEncodedSessionImage(accountId int, options types.SessionRequestPayload)
 		}
 		unratedVehicles, ratingVehicles := stats.SortAndSplitVehicles(sessionData.Diff.Vehicles, averages, unratedSortOptions, ratingSortOptions)
 
+		// Leaderboard position is optional, the overview card is rendered without it
+		var ratingPosition *wotblitz.PlayerLeaderboard
+		var ratingSeason *wotblitz.RatingSeason
+		if session.ShowRatingOverview(sessionData.Diff, sessionData.Selected) {
+			ratingPosition, ratingSeason, err = rating.GetLeaderboardPosition(accountId, 2)
+			if err != nil {
+				log.Warn().Err(err).Msg("failed to get leaderboard position")
+			}
+		}
+
 		statsCards, err := session.SnapshotToSession(session.ExportInput{
 			SessionStats:           sessionData.Diff,
 			CareerStats:            sessionData.Selected,
@@ -215,9 +239,12 @@ func getEncodedSessionImage(accountId int, options types.SessionRequestPayload)
 
 			VehicleGlossary:       vehiclesGlossary,
 			GlobalVehicleAverages: averages,
+
+			RatingPosition: ratingPosition,
+			RatingSeason:   ratingSeason,
 		}, session.ExportOptions{
-			Locale:                language.English,
-			LocalePrinter:         localization.GetPrinter(language.English),
+			Locale:                locale,
+			LocalePrinter:         localization.GetPrinter(locale),
 			Blocks:                blocks,
 			IncludeRatingVehicles: true,
 		})
@@ -233,6 +260,13 @@ func getEncodedSessionImage(accountId int, options types.SessionRequestPayload)
 			Session:       sessionData,
 			Cards:         statsCards,
 		}
+		if statsCards.RatingOverview != nil && statsCards.RatingOverview.LeagueIcon != "" {
+			icon, err := content.LoadCachedRemoteImage(statsCards.RatingOverview.LeagueIcon)
+			if err != nil {
+				log.Warn().Err(err).Msg("failed to load league icon")
+			}
+			player.RatingLeagueIcon = icon
+		}
 
 		renderOptions := render.RenderOptions{}
 
@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"strconv"
+
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
+	"github.com/cufee/aftermath-core/internal/logic/stats/rating"
+	"github.com/gofiber/fiber/v2"
+)
+
+func RatingSeasonProgressHandler(c *fiber.Ctx) error {
+	accountId, err := strconv.Atoi(c.Params("account"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
+
+	progress, err := rating.GetSeasonProgress(middleware.Providers(c).Live, accountId)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "rating.GetSeasonProgress"))
+	}
+
+	return c.JSON(server.NewResponse(progress))
+}
+
+func RatingSeasonResultsHandler(c *fiber.Ctx) error {
+	accountId, err := strconv.Atoi(c.Params("account"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
+
+	results, err := rating.GetSeasonResults(accountId)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "rating.GetSeasonResults"))
+	}
+
+	return c.JSON(server.NewResponse(results))
+}
+
+func RatingSeasonHistoryHandler(c *fiber.Ctx) error {
+	accountId, err := strconv.Atoi(c.Params("account"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
+	seasonId, err := strconv.Atoi(c.Params("season"))
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
+
+	history, err := rating.GetSeasonHistory(accountId, seasonId)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "rating.GetSeasonHistory"))
+	}
+
+	return c.JSON(server.NewResponse(history))
+}
@@ -2,13 +2,17 @@ package stats
 
 import (
 	"errors"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cufee/aftermath-core/dataprep/session"
 	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/localization"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
 	"github.com/cufee/aftermath-core/types"
 	"github.com/cufee/am-wg-proxy-next/v2/utils"
 	"golang.org/x/text/language"
@@ -16,7 +20,9 @@ import (
 	"github.com/cufee/aftermath-core/dataprep"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	"github.com/cufee/aftermath-core/internal/logic/cache"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
 	"github.com/cufee/aftermath-core/internal/logic/stats"
+	"github.com/cufee/aftermath-core/internal/logic/stats/rating"
 	"github.com/cufee/aftermath-core/internal/logic/stats/sessions"
 
 	"github.com/gofiber/fiber/v2"
@@ -36,7 +42,7 @@ func RecordPlayerSession(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	accountErrs, err := cache.RefreshSessionsAndAccounts(opts.Type(), opts.ReferenceID, utils.RealmFromPlayerID(accountId), accountId)
+	accountErrs, err := cache.RefreshSessionsAndAccounts(middleware.Providers(c).Cache, opts.Type(), opts.ReferenceID, utils.RealmFromPlayerID(accountId), accountId)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "cache.RefreshSessionsAndAccounts"))
 	}
@@ -60,7 +66,8 @@ func SessionFromIDHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	stats, err := getSessionStats(accountId, opts)
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	stats, err := getSessionStats(middleware.Providers(c), accountId, opts, nil, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getEncodedSessionImage"))
 	}
@@ -80,12 +87,12 @@ func SessionFromUserHandler(c *fiber.Ctx) error {
 		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
 	}
 
-	connection, err := database.FindUserConnection(user, models.ConnectionTypeWargaming)
+	connection, err := database.FindUserConnectionOrDefault(user, models.ConnectionTypeWargaming, c.Query("account"))
 	if err != nil {
 		if errors.Is(err, database.ErrConnectionNotFound) {
-			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "users.FindUserConnection"))
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnectionOrDefault"))
 		}
-		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "users.FindUserConnection"))
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnectionOrDefault"))
 	}
 
 	accountId, err := strconv.Atoi(connection.ExternalID)
@@ -93,7 +100,16 @@ func SessionFromUserHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(server.NewErrorResponse("invalid connection", "strconv.Atoi"))
 	}
 
-	stats, err := getSessionStats(accountId, opts)
+	// Users without a custom session reset use the realm-wide reset
+	var reset *models.SessionReset
+	if userData, err := database.GetUserByID(user); err == nil {
+		reset = userData.SessionReset
+	} else if !errors.Is(err, database.ErrUserNotFound) {
+		log.Warn().Err(err).Msg("failed to get user session reset")
+	}
+
+	locale := localization.ParseLocale(opts.Locale, c.Get(fiber.HeaderAcceptLanguage))
+	stats, err := getSessionStats(middleware.Providers(c), accountId, opts, reset, locale)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "getSessionStats"))
 	}
@@ -101,7 +117,7 @@ func SessionFromUserHandler(c *fiber.Ctx) error {
 	return c.JSON(server.NewResponse(stats))
 }
 
-func getSessionStats(accountId int, opts types.SessionRequestPayload) (*session.SessionStats, error) {
+func getSessionStats(providers wargaming.Providers, accountId int, opts types.SessionRequestPayload, reset *models.SessionReset, locale language.Tag) (*session.SessionStats, error) {
 	realm := utils.RealmFromPlayerID(accountId)
 
 	blocks, err := dataprep.ParseTags(opts.Presets...)
@@ -110,14 +126,14 @@ func getSessionStats(accountId int, opts types.SessionRequestPayload) (*session.
 	}
 
 	now := int(time.Now().Unix())
-	playerSession, err := sessions.GetCurrentPlayerSession(accountId, database.SessionGetOptions{LastBattleBefore: &now, ReferenceID: opts.ReferenceID})
+	playerSession, err := sessions.GetCurrentPlayerSession(providers.Live, accountId, sessions.WithSessionReset(database.SessionGetOptions{LastBattleBefore: &now, ReferenceID: opts.ReferenceID}, reset))
 	if err != nil {
 		if !errors.Is(err, sessions.ErrNoSessionCached) {
 			return nil, err
 		}
 		// Refresh the session cache in the background
 		go func(realm string, accountId int) {
-			accountErrs, err := cache.RefreshSessionsAndAccounts(models.SessionTypeDaily, opts.ReferenceID, realm, accountId)
+			accountErrs, err := cache.RefreshSessionsAndAccounts(providers.Cache, models.SessionTypeDaily, opts.ReferenceID, realm, accountId)
 			if err != nil || len(accountErrs) > 0 {
 				log.Err(err).Msg("failed to refresh session cache")
 			}
@@ -126,7 +142,7 @@ func getSessionStats(accountId int, opts types.SessionRequestPayload) (*session.
 
 	if playerSession.Account.ClanID != 0 {
 		go func() {
-			err := cache.CacheAllNewClanMembers(realm, playerSession.Account.ClanID)
+			err := cache.CacheAllNewClanMembers(providers.Cache, realm, playerSession.Account.ClanID)
 			if err != nil {
 				log.Err(err).Msg("failed to cache new clan members")
 			}
@@ -154,6 +170,16 @@ func getSessionStats(accountId int, opts types.SessionRequestPayload) (*session.
 
 	unratedVehicles, ratingVehicles := stats.SortAndSplitVehicles(playerSession.Diff.Vehicles, averages, stats.SortOptions{By: stats.SortByLastBattle, Limit: 5}, stats.SortOptions{By: stats.SortByLastBattle, Limit: 3})
 
+	// Leaderboard position is optional, the overview card is rendered without it
+	var ratingPosition *wotblitz.PlayerLeaderboard
+	var ratingSeason *wotblitz.RatingSeason
+	if session.ShowRatingOverview(playerSession.Diff, playerSession.Selected) {
+		ratingPosition, ratingSeason, err = rating.GetLeaderboardPosition(accountId, 2)
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to get leaderboard position")
+		}
+	}
+
 	statsCards, err := session.SnapshotToSession(session.ExportInput{
 		SessionStats:           playerSession.Diff,
 		CareerStats:            playerSession.Selected,
@@ -162,9 +188,12 @@ func getSessionStats(accountId int, opts types.SessionRequestPayload) (*session.
 
 		VehicleGlossary:       vehiclesGlossary,
 		GlobalVehicleAverages: averages,
+
+		RatingPosition: ratingPosition,
+		RatingSeason:   ratingSeason,
 	}, session.ExportOptions{
-		Locale:                language.English,
-		LocalePrinter:         localization.GetPrinter(language.English),
+		Locale:                locale,
+		LocalePrinter:         localization.GetPrinter(locale),
 		Blocks:                blocks,
 		IncludeRatingVehicles: true,
 	})
@@ -173,11 +202,63 @@ func getSessionStats(accountId int, opts types.SessionRequestPayload) (*session.
 	}
 
 	return &session.SessionStats{
-		Realm:      realm,
-		Locale:     language.English.String(),
-		LastBattle: playerSession.Account.LastBattleTime,
-		Clan:       playerSession.Account.ClanMember.Clan,
-		Account:    playerSession.Account.Account,
-		Cards:      statsCards,
+		Realm:        realm,
+		Locale:       locale.String(),
+		LastBattle:   playerSession.Account.LastBattleTime,
+		Clan:         playerSession.Account.ClanMember.Clan,
+		Account:      playerSession.Account.Account,
+		Cards:        statsCards,
+		Achievements: getSessionAchievements(accountId, reset, playerSession.Account.LastBattleTime, locale),
 	}, nil
 }
+
+/*
+getSessionAchievements returns medals earned since the last achievements snapshot, errors are not fatal and result in no achievements.
+Users with a custom session reset are compared to snapshots recorded at their reset, there are no achievements until the first one is recorded.
+*/
+func getSessionAchievements(accountId int, reset *models.SessionReset, lastBattleTime int, locale language.Tag) []session.SessionAchievement {
+	var referenceID string
+	if reset != nil {
+		referenceID = reset.Reference()
+	}
+
+	earned, err := sessions.GetAchievementsSinceSnapshot(accountId, referenceID, lastBattleTime)
+	if err != nil {
+		if !errors.Is(err, database.ErrNoAchievementsSnapshot) {
+			log.Warn().Err(err).Msg("failed to get session achievements")
+		}
+		return nil
+	}
+	if len(earned) == 0 {
+		return nil
+	}
+
+	var ids []string
+	for id := range earned {
+		ids = append(ids, id)
+	}
+
+	glossary, err := database.GetGlossaryAchievements(ids...)
+	if err != nil {
+		// This is definitely not fatal, but will look ugly
+		log.Warn().Err(err).Msg("failed to get achievements glossary")
+	}
+
+	var achievements []session.SessionAchievement
+	for _, id := range ids {
+		achievement := session.SessionAchievement{ID: id, Name: id, Count: earned[id]}
+		if data, ok := glossary[id]; ok {
+			achievement.Name = data.Name(locale)
+			achievement.ImageURL = data.ImageURL
+		}
+		achievements = append(achievements, achievement)
+	}
+
+	slices.SortFunc(achievements, func(a, b session.SessionAchievement) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return achievements
+}
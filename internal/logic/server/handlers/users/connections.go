@@ -29,9 +29,8 @@ func UpdateWargamingConnectionHandler(c *fiber.Ctx) error {
 
 	var update models.ConnectionUpdate
 	update.Metadata = map[string]interface{}{"verified": false}
-	update.ExternalID = &account
 
-	connection, err := database.UpdateUserConnection(user.ID, models.ConnectionTypeWargaming, update)
+	connection, err := database.UpdateUserConnection(user.ID, models.ConnectionTypeWargaming, account, update)
 	if err != nil {
 		if !errors.Is(err, database.ErrConnectionNotFound) {
 			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnection"))
@@ -44,3 +43,90 @@ func UpdateWargamingConnectionHandler(c *fiber.Ctx) error {
 
 	return c.JSON(server.NewResponse(connection))
 }
+
+func SetDefaultWargamingConnectionHandler(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("id path parameter is required", "c.Param"))
+	}
+
+	account := c.Params("account")
+	_, err := strconv.Atoi(account)
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
+
+	connection, err := database.SetDefaultUserConnection(userId, models.ConnectionTypeWargaming, account)
+	if err != nil {
+		if errors.Is(err, database.ErrConnectionNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.SetDefaultUserConnection"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.SetDefaultUserConnection"))
+	}
+
+	return c.JSON(server.NewResponse(connection))
+}
+
+var GetUserConnectionsHandler = NewGetConnectionsHandler("id")
+
+/*
+RemoveUserConnectionHandler unlinks a single account, the account query parameter is required
+*/
+var RemoveUserConnectionHandler = NewRemoveConnectionsHandler("id", true, nil)
+
+/*
+NewGetConnectionsHandler returns a handler listing connections of the user id in param, it is shared with moderation routes
+*/
+func NewGetConnectionsHandler(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userId := c.Params(param)
+		if userId == "" {
+			return c.Status(400).JSON(server.NewErrorResponse(param+" path parameter is required", "c.Param"))
+		}
+
+		connections, err := database.GetUserConnections(userId)
+		if err != nil && !errors.Is(err, database.ErrConnectionNotFound) {
+			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserConnections"))
+		}
+
+		return c.JSON(server.NewResponse(connections))
+	}
+}
+
+/*
+NewRemoveConnectionsHandler returns a handler unlinking connections of the user id in param, the account query parameter limits removal to a single account.
+When requireAccount is false and no account is provided, all connections of the type are removed. onRemoved is optional and called with removed connections.
+*/
+func NewRemoveConnectionsHandler(param string, requireAccount bool, onRemoved func(c *fiber.Ctx, userId string, removed []models.UserConnection)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userId := c.Params(param)
+		if userId == "" {
+			return c.Status(400).JSON(server.NewErrorResponse(param+" path parameter is required", "c.Param"))
+		}
+		connectionType, valid := models.ParseConnectionType(c.Params("type"))
+		if !valid {
+			return c.Status(400).JSON(server.NewErrorResponse("invalid connection type", "c.Param"))
+		}
+
+		var accounts []string
+		if account := c.Query("account"); account != "" {
+			accounts = append(accounts, account)
+		}
+		if requireAccount && len(accounts) == 0 {
+			return c.Status(400).JSON(server.NewErrorResponse("account query parameter is required", "c.Query"))
+		}
+
+		removed, err := database.UnlinkUserConnections(userId, connectionType, accounts...)
+		if err != nil {
+			if errors.Is(err, database.ErrConnectionNotFound) {
+				return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.UnlinkUserConnections"))
+			}
+			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UnlinkUserConnections"))
+		}
+
+		if onRemoved != nil {
+			onRemoved(c, userId, removed)
+		}
+		return c.JSON(server.NewResponse(removed))
+	}
+}
@@ -0,0 +1,56 @@
+package users
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRemoveUserConnection(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+
+	storage := memory.NewStorage()
+	database.DefaultStorage = storage
+
+	for _, account := range []string{"1013072123", "579178315"} {
+		_, err := storage.AddUserConnection("user", models.ConnectionTypeWargaming, account, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := fiber.New()
+	app.Delete("/users/:id/connections/:type", RemoveUserConnectionHandler)
+
+	// Users can only unlink one account at a time
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"/users/user/connections/wargaming", 400},
+		{"/users/user/connections/wargaming?account=1013072123", 200},
+		{"/users/user/connections/wargaming?account=1013072123", 404},
+	}
+	for _, tc := range cases {
+		res, err := app.Test(httptest.NewRequest("DELETE", tc.path, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %d", tc.path, tc.status, res.StatusCode)
+		}
+	}
+
+	connections, err := storage.GetUserConnections("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(connections) != 1 || connections[0].ExternalID != "579178315" {
+		t.Errorf("unexpected connections %+v", connections)
+	}
+}
@@ -1,7 +1,10 @@
 package users
 
 import (
+	"errors"
+
 	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	"github.com/cufee/aftermath-core/permissions/v2"
 	"github.com/cufee/aftermath-core/types"
@@ -23,8 +26,64 @@ func GetUserHandler(c *fiber.Ctx) error {
 		user.User.Permissions = permissions.User.Encode()
 	}
 
+	restrictions, err := database.FindUserRestrictions(user.ID)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserRestrictions"))
+	}
+
+	// The response is the complete user with restrictions as an additional field
 	var extended types.User
 	extended.CompleteUser = user
+	extended.Restrictions = restrictions
+	if extended.Restrictions == nil {
+		extended.Restrictions = []types.UserRestriction{}
+	}
+
+	return c.JSON(server.NewResponse(extended))
+}
+
+func UpdateSessionResetHandler(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("id path parameter is required", "c.Param"))
+	}
+
+	var reset models.SessionReset
+	err := c.BodyParser(&reset)
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "c.BodyParser"))
+	}
+	err = reset.Validate()
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "models.SessionReset.Validate"))
+	}
+
+	_, err = database.GetOrCreateUserByID(userId)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetOrCreateUserByID"))
+	}
+
+	user, err := database.UpdateUserSessionReset(userId, &reset)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserSessionReset"))
+	}
+
+	return c.JSON(server.NewResponse(user.SessionReset))
+}
+
+func RemoveSessionResetHandler(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	if userId == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("id path parameter is required", "c.Param"))
+	}
+
+	_, err := database.UpdateUserSessionReset(userId, nil)
+	if err != nil {
+		if errors.Is(err, database.ErrUserNotFound) {
+			return c.Status(404).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserSessionReset"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserSessionReset"))
+	}
 
-	return c.JSON(server.NewResponse(user))
+	return c.JSON(server.NewResponse(userId))
 }
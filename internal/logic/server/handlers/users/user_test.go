@@ -0,0 +1,43 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetUserResponseShape(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = memory.NewStorage()
+
+	app := fiber.New()
+	app.Get("/users/:id", GetUserHandler)
+
+	res, err := app.Test(httptest.NewRequest("GET", "/users/user", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Complete user fields stay at the top level, restrictions are an extra field
+	for _, key := range []string{"id", "permissions", "connections", "subscriptions"} {
+		if _, ok := body.Data[key]; !ok {
+			t.Errorf("expected %s in the response, got %v", key, body.Data)
+		}
+	}
+	if string(body.Data["restrictions"]) != "[]" {
+		t.Errorf("expected empty restrictions, got %s", body.Data["restrictions"])
+	}
+}
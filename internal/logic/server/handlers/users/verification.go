@@ -4,21 +4,29 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/core/server"
 	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
 	"github.com/cufee/aftermath-core/types"
+	wgUtils "github.com/cufee/am-wg-proxy-next/v2/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/text/language"
 )
 
-var frontendURL = utils.MustGetEnv("FRONTEND_URL")
-var authWargamingAppID = utils.MustGetEnv("AUTH_WARGAMING_APP_ID")
+// Read on first use, same as the official API settings in wotblitz
+var frontendURL = sync.OnceValue(func() string { return utils.MustGetEnv("FRONTEND_URL") })
+var authWargamingAppID = sync.OnceValue(func() string { return utils.MustGetEnv("AUTH_WARGAMING_APP_ID") })
+
+var tokenValidator wargaming.AccessTokenValidator = &wotblitz.TokenValidator{}
 
 func CompleteUserVerificationHandler(c *fiber.Ctx) error {
 	nonceID := c.Params("nonce")
@@ -34,6 +42,13 @@ func CompleteUserVerificationHandler(c *fiber.Ctx) error {
 	if payload.AccountID == "" {
 		return c.Status(400).JSON(server.NewErrorResponse("payload accountID is required", "c.BodyParser"))
 	}
+	if payload.AccessToken == "" {
+		return c.Status(400).JSON(server.NewErrorResponse("payload accessToken is required", "c.BodyParser"))
+	}
+	accountID, err := strconv.Atoi(payload.AccountID)
+	if err != nil {
+		return c.Status(400).JSON(server.NewErrorResponseFromError(err, "strconv.Atoi"))
+	}
 
 	nonce, err := database.GetNonceByID(nonceID)
 	if err != nil {
@@ -58,6 +73,29 @@ func CompleteUserVerificationHandler(c *fiber.Ctx) error {
 		return c.Status(404).JSON(server.NewErrorResponseFromError(err, "users.FindUserByID"))
 	}
 
+	// The nonce is created for a user on a restricted route, but the restriction could have been added since
+	restriction, err := database.FindActiveUserRestriction(user.ID, models.RestrictionScopeConnections)
+	if err != nil {
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindActiveUserRestriction"))
+	}
+	if restriction != nil {
+		return c.Status(403).JSON(server.NewErrorResponse(restriction.Message(), "restriction"))
+	}
+
+	if payload.Expired() {
+		return c.Status(401).JSON(server.NewErrorResponse("access token expired", "payload.Expired"))
+	}
+	tokenAccountID, err := tokenValidator.ValidateAccessToken(wgUtils.RealmFromPlayerID(accountID), accountID, payload.AccessToken)
+	if err != nil {
+		if errors.Is(err, wargaming.ErrInvalidAccessToken) {
+			return c.Status(401).JSON(server.NewErrorResponseFromError(err, "tokenValidator.ValidateAccessToken"))
+		}
+		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "tokenValidator.ValidateAccessToken"))
+	}
+	if tokenAccountID != accountID {
+		return c.Status(403).JSON(server.NewErrorResponse("access token was issued for a different account", "tokenValidator.ValidateAccessToken"))
+	}
+
 	// Mark all connections for this account as unverified
 	err = database.UpdateManyConnectionsByReferenceID(payload.AccountID, models.ConnectionTypeWargaming, models.ConnectionUpdate{Metadata: map[string]interface{}{"verified": false}})
 	if err != nil {
@@ -66,9 +104,8 @@ func CompleteUserVerificationHandler(c *fiber.Ctx) error {
 
 	var update models.ConnectionUpdate
 	update.Metadata = map[string]interface{}{"verified": true}
-	update.ExternalID = &payload.AccountID
 
-	connection, err := database.UpdateUserConnection(user.ID, models.ConnectionTypeWargaming, update)
+	connection, err := database.UpdateUserConnection(user.ID, models.ConnectionTypeWargaming, payload.AccountID, update)
 	if err != nil {
 		if !errors.Is(err, database.ErrConnectionNotFound) {
 			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindUserConnection"))
@@ -79,10 +116,12 @@ func CompleteUserVerificationHandler(c *fiber.Ctx) error {
 		}
 	}
 
-	// Update user content reference ID
-	_, err = database.UpdateUserContentReferenceID[string](user.ID, models.UserContentTypePersonalBackground, payload.AccountID)
-	if err != nil && !errors.Is(database.ErrUserContentNotFound, err) {
-		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserContent"))
+	// The personal background follows the default account, verifying a secondary account should not move it
+	if connection.Default {
+		_, err = database.UpdateUserContentReferenceID[string](user.ID, models.UserContentTypePersonalBackground, payload.AccountID)
+		if err != nil && !errors.Is(database.ErrUserContentNotFound, err) {
+			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.UpdateUserContent"))
+		}
 	}
 
 	return c.JSON(server.NewResponse(connection))
@@ -108,7 +147,8 @@ func StartUserVerificationHandler(c *fiber.Ctx) error {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.NewNonce"))
 	}
 
-	link, err := loginUrlFromRealm(realm, language.English.String(), nonce)
+	locale := localization.ParseLocale(c.Query("locale"), c.Get(fiber.HeaderAcceptLanguage))
+	link, err := loginUrlFromRealm(realm, locale.String(), nonce)
 	if err != nil {
 		return c.Status(500).JSON(server.NewErrorResponseFromError(err, "loginUrlFromRealm"))
 	}
@@ -129,5 +169,5 @@ func loginUrlFromRealm(realm, language, nonce string) (string, error) {
 		return "", errors.New("unknown realm")
 	}
 
-	return fmt.Sprintf("%s/wot/auth/login/?redirect_uri=%s&language=%s&application_id=%s", base, url.QueryEscape(fmt.Sprintf("%s/auth/wargaming/redirect/%s", frontendURL, nonce)), language, authWargamingAppID), nil
+	return fmt.Sprintf("%s/wot/auth/login/?redirect_uri=%s&language=%s&application_id=%s", base, url.QueryEscape(fmt.Sprintf("%s/auth/wargaming/redirect/%s", frontendURL(), nonce)), language, authWargamingAppID()), nil
 }
@@ -0,0 +1,176 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/types"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCompleteUserVerification(t *testing.T) {
+	previousStorage, previousValidator := database.DefaultStorage, tokenValidator
+	t.Cleanup(func() { database.DefaultStorage, tokenValidator = previousStorage, previousValidator })
+
+	storage := memory.NewStorage()
+	database.DefaultStorage = storage
+	tokenValidator = &wargaming.FixtureTokenValidator{Tokens: map[string]int{"valid": 1013072123, "other": 579178315}}
+
+	_, err := storage.CreateUser("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Post("/verify/:nonce", CompleteUserVerificationHandler)
+
+	valid := time.Now().Add(time.Hour).Unix()
+	cases := map[string]struct {
+		payload types.UserVerificationPayload
+		status  int
+	}{
+		"valid":    {types.UserVerificationPayload{AccountID: "1013072123", AccessToken: "valid", AccessTokenExpiresAt: valid}, 200},
+		"mismatch": {types.UserVerificationPayload{AccountID: "1013072123", AccessToken: "other", AccessTokenExpiresAt: valid}, 403},
+		"invalid":  {types.UserVerificationPayload{AccountID: "1013072123", AccessToken: "invalid", AccessTokenExpiresAt: valid}, 401},
+		"expired":  {types.UserVerificationPayload{AccountID: "1013072123", AccessToken: "valid", AccessTokenExpiresAt: time.Now().Add(-time.Hour).Unix()}, 401},
+		"missing":  {types.UserVerificationPayload{AccountID: "1013072123", AccessTokenExpiresAt: valid}, 400},
+	}
+	for name, tc := range cases {
+		nonce, err := storage.NewNonce("user", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, _ := json.Marshal(tc.payload)
+		req := httptest.NewRequest("POST", "/verify/"+nonce, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %d", name, tc.status, res.StatusCode)
+		}
+	}
+
+	connection, err := storage.GetUserConnectionByExternalID("user", models.ConnectionTypeWargaming, "1013072123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified, _ := connection.Metadata["verified"].(bool); !verified {
+		t.Errorf("expected connection to be verified")
+	}
+
+	// Restrictions added after the nonce was issued still apply
+	nonce, err := storage.NewNonce("user", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storage.AddUserRestriction(models.UserRestriction{UserID: "user", Scopes: []string{models.RestrictionScopeConnections}, ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(types.UserVerificationPayload{AccountID: "579178315", AccessToken: "other", AccessTokenExpiresAt: valid})
+	req := httptest.NewRequest("POST", "/verify/"+nonce, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 403 {
+		t.Errorf("restricted: expected status 403, got %d", res.StatusCode)
+	}
+}
+
+func TestVerifySecondaryAccount(t *testing.T) {
+	previousStorage, previousValidator := database.DefaultStorage, tokenValidator
+	t.Cleanup(func() { database.DefaultStorage, tokenValidator = previousStorage, previousValidator })
+
+	storage := memory.NewStorage()
+	database.DefaultStorage = storage
+	tokenValidator = &wargaming.FixtureTokenValidator{Tokens: map[string]int{"default": 1013072123, "secondary": 579178315}}
+
+	_, err := storage.CreateUser("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storage.AddUserConnection("user", models.ConnectionTypeWargaming, "1013072123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.UpdateUserContent("user", "1013072123", models.UserContentTypePersonalBackground, "https://example.com/background.png", nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Post("/verify/:nonce", CompleteUserVerificationHandler)
+	app.Delete("/users/:id/connections/:type", RemoveUserConnectionHandler)
+
+	verify := func(accountID, token string) {
+		t.Helper()
+
+		nonce, err := storage.NewNonce("user", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := json.Marshal(types.UserVerificationPayload{AccountID: accountID, AccessToken: token, AccessTokenExpiresAt: time.Now().Add(time.Hour).Unix()})
+		req := httptest.NewRequest("POST", "/verify/"+nonce, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != 200 {
+			t.Fatalf("%s: expected status 200, got %d", accountID, res.StatusCode)
+		}
+	}
+
+	// Verifying a secondary account leaves the background on the default account
+	verify("579178315", "secondary")
+	content, err := storage.GetUserContent("user", models.UserContentTypePersonalBackground)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.ReferenceID != "1013072123" {
+		t.Errorf("expected background to stay on the default account, got %q", content.ReferenceID)
+	}
+
+	// Unlinking the secondary account keeps the background
+	res, err := app.Test(httptest.NewRequest("DELETE", "/users/user/connections/wargaming?account=579178315", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 200 {
+		t.Fatalf("expected status 200, got %d", res.StatusCode)
+	}
+	_, err = storage.GetUserContent("user", models.UserContentTypePersonalBackground)
+	if err != nil {
+		t.Errorf("expected background to be kept, got %v", err)
+	}
+
+	// Verifying the default account still moves the background to it
+	err = database.UpdateUserContent("user", "", models.UserContentTypePersonalBackground, "https://example.com/background.png", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify("1013072123", "default")
+	content, err = storage.GetUserContent("user", models.UserContentTypePersonalBackground)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.ReferenceID != "1013072123" {
+		t.Errorf("expected background on the default account, got %q", content.ReferenceID)
+	}
+}
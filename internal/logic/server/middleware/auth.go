@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cufee/aftermath-core/internal/core/auth"
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/cufee/aftermath-core/permissions/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+const localsActorKey = "actor"
+
+/*
+Authenticate resolves the acting user from a signed token in the Authorization header and saves it to the request context
+*/
+func Authenticate(signer *auth.Signer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			return c.Status(401).JSON(server.NewErrorResponse("authorization token required", "middleware.Authenticate"))
+		}
+
+		claims, err := signer.Parse(token)
+		if err != nil {
+			return c.Status(401).JSON(server.NewErrorResponseFromError(err, "auth.Parse"))
+		}
+
+		actor, err := database.GetUserByID(claims.UserID)
+		if err != nil {
+			if errors.Is(err, database.ErrUserNotFound) {
+				return c.Status(401).JSON(server.NewErrorResponseFromError(err, "database.GetUserByID"))
+			}
+			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.GetUserByID"))
+		}
+
+		c.Locals(localsActorKey, actor)
+		return c.Next()
+	}
+}
+
+/*
+RequirePermissions rejects requests from actors that are missing any of the permissions, it must be used after Authenticate
+*/
+func RequirePermissions(required permissions.Permissions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor, ok := Actor(c)
+		if !ok {
+			return c.Status(401).JSON(server.NewErrorResponse("request is not authenticated", "middleware.RequirePermissions"))
+		}
+		if !actor.Permissions().Has(required) {
+			return c.Status(403).JSON(server.NewErrorResponse("user has no permissions", "middleware.RequirePermissions"))
+		}
+		return c.Next()
+	}
+}
+
+/*
+Actor returns the user resolved by Authenticate
+*/
+func Actor(c *fiber.Ctx) (models.CompleteUser, bool) {
+	actor, ok := c.Locals(localsActorKey).(models.CompleteUser)
+	return actor, ok
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/auth"
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/permissions/v2"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestModerationPermissions(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	storage := memory.NewStorage()
+	database.DefaultStorage = storage
+
+	moderator, err := storage.CreateUser("moderator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	moderator.Permissions = permissions.GlobalModerator.Encode()
+	_, err = storage.UpdateUser(moderator.ID, moderator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storage.CreateUser("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := auth.NewSigner("secret")
+	app := fiber.New()
+	app.Post("/subscriptions", Authenticate(signer), RequirePermissions(permissions.CreateUserSubscription), func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	token := func(userID string, signer *auth.Signer, duration time.Duration) string {
+		token, err := signer.NewToken(userID, duration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	cases := []struct {
+		name   string
+		header string
+		status int
+	}{
+		{"missing token", "", 401},
+		{"bad signature", "Bearer " + token("moderator", auth.NewSigner("other"), time.Minute), 401},
+		{"expired token", "Bearer " + token("moderator", signer, -time.Minute), 401},
+		{"unknown user", "Bearer " + token("unknown", signer, time.Minute), 401},
+		{"missing permission", "Bearer " + token("user", signer, time.Minute), 403},
+		{"moderator", "Bearer " + token("moderator", signer, time.Minute), 200},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("POST", "/subscriptions", nil)
+		if c.header != "" {
+			req.Header.Set(fiber.HeaderAuthorization, c.header)
+		}
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != c.status {
+			t.Errorf("%s: expected status %d, got %d", c.name, c.status, res.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/gofiber/fiber/v2"
+)
+
+const localsProvidersKey = "providers"
+
+var unconfiguredProvider = &wargaming.FixtureProvider{Err: wargaming.ErrProviderNotConfigured}
+
+/*
+WithProviders saves stats providers to the request context, handlers read them with Providers
+*/
+func WithProviders(providers wargaming.Providers) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(localsProvidersKey, providers)
+		return c.Next()
+	}
+}
+
+/*
+Providers returns the stats providers saved by WithProviders, missing providers return ErrProviderNotConfigured from every call
+*/
+func Providers(c *fiber.Ctx) wargaming.Providers {
+	providers, _ := c.Locals(localsProvidersKey).(wargaming.Providers)
+	if providers.Live == nil {
+		providers.Live = unconfiguredProvider
+	}
+	if providers.Cache == nil {
+		providers.Cache = unconfiguredProvider
+	}
+	return providers
+}
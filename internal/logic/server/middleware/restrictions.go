@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/server"
+	"github.com/gofiber/fiber/v2"
+)
+
+/*
+RejectRestricted rejects requests for a user with an active restriction covering any of the scopes, the user id is read from the path parameter
+*/
+func RejectRestricted(param string, scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userId := c.Params(param)
+		if userId == "" {
+			return c.Next()
+		}
+
+		restriction, err := database.FindActiveUserRestriction(userId, scopes...)
+		if err != nil {
+			return c.Status(500).JSON(server.NewErrorResponseFromError(err, "database.FindActiveUserRestriction"))
+		}
+		if restriction != nil {
+			return c.Status(403).JSON(server.NewErrorResponse(restriction.Message(), "restriction"))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRejectRestricted(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+
+	storage := memory.NewStorage()
+	database.DefaultStorage = storage
+
+	restrictions := []models.UserRestriction{
+		{UserID: "restricted", Scopes: []string{models.RestrictionScopeRender}, ExpiresAt: time.Now().Add(time.Hour)},
+		{UserID: "expired", ExpiresAt: time.Now().Add(-time.Hour)},
+		{UserID: "other-scope", Scopes: []string{models.RestrictionScopeContent}, ExpiresAt: time.Now().Add(time.Hour)},
+		{UserID: "global", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	for _, restriction := range restrictions {
+		_, err := storage.AddUserRestriction(restriction)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := fiber.New()
+	app.Post("/render/:id", RejectRestricted("id", models.RestrictionScopeRender), func(c *fiber.Ctx) error {
+		return c.SendStatus(200)
+	})
+
+	cases := map[string]int{
+		"restricted":  403,
+		"expired":     200,
+		"other-scope": 200,
+		"global":      403,
+		"clean":       200,
+	}
+	for userId, status := range cases {
+		res, err := app.Test(httptest.NewRequest("POST", "/render/"+userId, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.StatusCode != status {
+			t.Errorf("%s: expected status %d, got %d", userId, status, res.StatusCode)
+		}
+	}
+}
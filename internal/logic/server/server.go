@@ -3,12 +3,17 @@ package server
 import (
 	"os"
 
+	"github.com/cufee/aftermath-core/internal/core/auth"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/server/handlers/accounts"
 	"github.com/cufee/aftermath-core/internal/logic/server/handlers/content"
 	"github.com/cufee/aftermath-core/internal/logic/server/handlers/moderation"
 	"github.com/cufee/aftermath-core/internal/logic/server/handlers/render"
 	"github.com/cufee/aftermath-core/internal/logic/server/handlers/stats"
 	"github.com/cufee/aftermath-core/internal/logic/server/handlers/users"
+	"github.com/cufee/aftermath-core/internal/logic/server/middleware"
+	"github.com/cufee/aftermath-core/permissions/v2"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
@@ -23,6 +28,7 @@ func Start() {
 	})
 	app.Use(fiberzerolog.New())
 	app.Use(recover.New())
+	app.Use(middleware.WithProviders(wargaming.Clients))
 
 	app.Get("/healthy", func(c *fiber.Ctx) error {
 		return c.SendStatus(200)
@@ -32,41 +38,75 @@ func Start() {
 
 	renderV1 := v1.Group("/render")
 	renderV1.Post("/replay", render.ReplayFromPayload)
-	renderV1.Post("/period/user/:id", render.PeriodFromUserHandler)
+	renderV1.Post("/period/user/:id", middleware.RejectRestricted("id", models.RestrictionScopeRender), render.PeriodFromUserHandler)
 	renderV1.Post("/period/account/:account", render.PeriodFromIDHandler)
-	renderV1.Post("/session/user/:id", render.SessionFromUserHandler)
+	renderV1.Post("/session/user/:id", middleware.RejectRestricted("id", models.RestrictionScopeRender), render.SessionFromUserHandler)
 	renderV1.Post("/session/account/:account", render.SessionFromIDHandler)
 
 	statsV1 := v1.Group("/stats")
 	statsV1.Post("/session/user/:id", stats.SessionFromUserHandler)
 	statsV1.Post("/session/account/:account", stats.SessionFromIDHandler)
 	statsV1.Post("/session/account/:account/reset", stats.RecordPlayerSession)
+	statsV1.Get("/rating/account/:account", stats.RatingSeasonProgressHandler)
+	statsV1.Get("/rating/account/:account/seasons", stats.RatingSeasonResultsHandler)
+	statsV1.Get("/rating/account/:account/seasons/:season", stats.RatingSeasonHistoryHandler)
 
 	accountsV1 := v1.Group("/accounts")
 	accountsV1.Get("/search", accounts.SearchAccountsHandler)
+	accountsV1.Get("/:account/wn8", accounts.GetAccountWN8Handler)
 
 	usersV1 := v1.Group("/users")
 	usersV1.Get("/:id", users.GetUserHandler)
-	usersV1.Post("/:id/content", users.UploadUserContentHandler)
+	usersV1.Post("/:id/content", middleware.RejectRestricted("id", models.RestrictionScopeContent), users.UploadUserContentHandler)
 	usersV1.Get("/:id/content/select", content.PreviewCurrentBackgroundSelectionHandler)
-	usersV1.Post("/:id/content/select/:index", users.SelectBackgroundPresetHandler)
-	usersV1.Post("/:id/connections/wargaming/:account", users.UpdateWargamingConnectionHandler)
+	usersV1.Post("/:id/content/select/:index", middleware.RejectRestricted("id", models.RestrictionScopeContent), users.SelectBackgroundPresetHandler)
+	usersV1.Put("/:id/session-reset", users.UpdateSessionResetHandler)
+	usersV1.Delete("/:id/session-reset", users.RemoveSessionResetHandler)
+	usersV1.Get("/:id/connections", users.GetUserConnectionsHandler)
+	usersV1.Delete("/:id/connections/:type", middleware.RejectRestricted("id", models.RestrictionScopeConnections), users.RemoveUserConnectionHandler)
+	usersV1.Post("/:id/connections/wargaming/:account", middleware.RejectRestricted("id", models.RestrictionScopeConnections), users.UpdateWargamingConnectionHandler)
+	usersV1.Post("/:id/connections/wargaming/:account/default", middleware.RejectRestricted("id", models.RestrictionScopeConnections), users.SetDefaultWargamingConnectionHandler)
 
 	connectionsV1 := v1.Group("/connections")
-	connectionsV1.Get("/wargaming/verify/:id", users.StartUserVerificationHandler)
+	connectionsV1.Get("/wargaming/verify/:id", middleware.RejectRestricted("id", models.RestrictionScopeConnections), users.StartUserVerificationHandler)
 	connectionsV1.Post("/wargaming/verify/:nonce", users.CompleteUserVerificationHandler)
 
-	moderationV1 := v1.Group("/moderation")
+	moderationV1 := v1.Group("/moderation", middleware.Authenticate(auth.DefaultSigner))
 	moderationV1.Get("/permissions", moderation.GetPermissionsMapHandler)
 
-	moderationV1.Get("/content/rotate", moderation.RotateBackgroundImagesHandler)
-	moderationV1.Post("/content/upload", moderation.UploadBackgroundImageHandler)
-
-	moderationV1.Get("/subscriptions/user/:userId", moderation.GetUserSubscriptionsHandler)
-	moderationV1.Post("/subscriptions", moderation.CreateUserSubscriptionsHandler)
-	moderationV1.Get("/subscriptions/:id", moderation.GetSubscriptionHandler)
-	moderationV1.Patch("/subscriptions/:id", moderation.UpdateSubscriptionHandler)
-	moderationV1.Post("/connections", moderation.ForceUpdateConnectionHandler)
+	moderationV1.Get("/content/rotate", middleware.RequirePermissions(permissions.UploadBackgroundPreset), moderation.RotateBackgroundImagesHandler)
+	moderationV1.Post("/content/upload", middleware.RequirePermissions(permissions.UploadBackgroundPreset), moderation.UploadBackgroundImageHandler)
+
+	moderationV1.Get("/subscriptions/user/:userId", middleware.RequirePermissions(permissions.RetrieveUserSubscriptions), moderation.GetUserSubscriptionsHandler)
+	moderationV1.Post("/subscriptions", middleware.RequirePermissions(permissions.CreateUserSubscription), moderation.CreateUserSubscriptionsHandler)
+	moderationV1.Get("/subscriptions/:id", middleware.RequirePermissions(permissions.RetrieveUserSubscriptions), moderation.GetSubscriptionHandler)
+	moderationV1.Patch("/subscriptions/:id", middleware.RequirePermissions(permissions.ExtendUserSubscription), moderation.UpdateSubscriptionHandler)
+
+	moderationV1.Get("/restrictions/user/:userId", middleware.RequirePermissions(permissions.RetrieveUserRestrictions), moderation.GetUserRestrictionsHandler)
+	moderationV1.Post("/restrictions", middleware.RequirePermissions(permissions.CreateUserRestriction), moderation.CreateRestrictionHandler)
+	moderationV1.Get("/restrictions/:id", middleware.RequirePermissions(permissions.RetrieveUserRestrictions), moderation.GetRestrictionHandler)
+	moderationV1.Patch("/restrictions/:id", middleware.RequirePermissions(permissions.CreateUserRestriction|permissions.RemoveUserRestriction), moderation.UpdateRestrictionHandler)
+	moderationV1.Delete("/restrictions/:id", middleware.RequirePermissions(permissions.RemoveUserRestriction), moderation.DeleteRestrictionHandler)
+
+	moderationV1.Get("/users/:userId/permissions", middleware.RequirePermissions(permissions.ManageUserRoles), moderation.GetUserPermissionsHandler)
+	moderationV1.Patch("/users/:userId/permissions", middleware.RequirePermissions(permissions.ManageUserRoles), moderation.UpdateUserPermissionsHandler)
+
+	moderationV1.Get("/audit", middleware.RequirePermissions(permissions.RetrieveAuditLog), moderation.GetAuditLogHandler)
+
+	moderationV1.Post("/connections", middleware.RequirePermissions(permissions.ManageUserConnectionVerification), moderation.ForceUpdateConnectionHandler)
+	moderationV1.Get("/connections/user/:userId", middleware.RequirePermissions(permissions.RetrieveUserConnections), moderation.GetUserConnectionsHandler)
+	moderationV1.Delete("/connections/user/:userId/:type", middleware.RequirePermissions(permissions.RemoveUserConnection), moderation.RemoveUserConnectionHandler)
+
+	adminV1 := v1.Group("/admin", middleware.Authenticate(auth.DefaultSigner), middleware.RequirePermissions(permissions.ManageTasks))
+	adminV1.Get("/tasks", moderation.GetTasksHandler)
+	adminV1.Get("/tasks/counts", moderation.GetTaskCountsHandler)
+	adminV1.Post("/tasks/sessions/:realm", moderation.CreateSessionTasksHandler)
+	adminV1.Get("/tasks/:id", moderation.GetTaskHandler)
+	adminV1.Get("/tasks/:id/logs", moderation.GetTaskLogsHandler)
+	adminV1.Post("/tasks/:id/retry", moderation.RetryTaskHandler)
+	adminV1.Post("/tasks/:id/cancel", moderation.CancelTaskHandler)
+	adminV1.Get("/schedules", moderation.GetSchedulesHandler)
+	adminV1.Post("/schedules/:name/run", moderation.RunScheduledJobHandler)
 
 	panic(app.Listen(":" + os.Getenv("PORT")))
 }
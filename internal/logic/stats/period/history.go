@@ -0,0 +1,136 @@
+package period
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	core "github.com/cufee/aftermath-core/internal/core/stats"
+	"github.com/cufee/aftermath-core/internal/logic/external/blitzstars"
+	"github.com/cufee/aftermath-core/internal/logic/stats"
+)
+
+type Source string
+
+const (
+	SourceCareer     = Source("career")
+	SourceSnapshots  = Source("snapshots")
+	SourceRollups    = Source("rollups")
+	SourceBlitzStars = Source("blitzstars")
+)
+
+var errNoHistory = errors.New("no history found")
+
+/*
+baseline holds cumulative stats for each vehicle as of Start, vehicles that are missing had no battles before Start
+*/
+type baseline struct {
+	Source   Source
+	Start    time.Time
+	Vehicles map[int]core.ReducedStatsFrame
+}
+
+/*
+historyProvider returns a baseline as close as possible to start without going past it, errNoHistory is returned when the account was not tracked long enough
+*/
+type historyProvider func(accountID int, start time.Time, days int) (baseline, error)
+
+/*
+historyProviders are tried in order, our own data is preferred so that periods do not depend on BlitzStars being available
+*/
+var historyProviders = []historyProvider{snapshotHistory, rollupHistory, blitzStarsHistory}
+
+/*
+maxStartDrift is how much earlier than requested a stored baseline can start, rollups are recorded once per period and will rarely match the requested start
+*/
+func maxStartDrift(days int) time.Duration {
+	return max(durationDay, durationDay*time.Duration(days)/5)
+}
+
+func getBaseline(accountID int, start time.Time, days int) (baseline, error) {
+	var errs []error
+	for _, provider := range historyProviders {
+		b, err := provider(accountID, start, days)
+		if err == nil {
+			return b, nil
+		}
+		errs = append(errs, err)
+	}
+	return baseline{}, errors.Join(errs...)
+}
+
+func snapshotHistory(accountID int, start time.Time, days int) (baseline, error) {
+	realmWide := ""
+	snapshot, err := database.GetPlayerSessionSnapshot(accountID, database.SessionGetOptions{Type: models.SessionTypeDaily, ReferenceID: &realmWide, CreatedBefore: &start})
+	if err != nil {
+		if errors.Is(err, database.ErrNoSessionCache) {
+			return baseline{}, errNoHistory
+		}
+		return baseline{}, err
+	}
+	if snapshot.CreatedAt.Before(start.Add(-maxStartDrift(days))) {
+		return baseline{}, errNoHistory
+	}
+	return snapshotBaseline(SourceSnapshots, snapshot.CreatedAt, snapshot.Session), nil
+}
+
+func rollupHistory(accountID int, start time.Time, days int) (baseline, error) {
+	var selected *models.SessionRollup
+	for _, period := range models.AllRollupPeriods {
+		rollup, err := database.GetSessionRollup(period, accountID, start)
+		if err != nil {
+			if errors.Is(err, database.ErrNoSessionRollup) {
+				continue
+			}
+			return baseline{}, err
+		}
+		if rollup.RecordedAt.IsZero() {
+			continue
+		}
+		if selected == nil || rollup.RecordedAt.After(selected.RecordedAt) {
+			selected = &rollup
+		}
+	}
+	if selected == nil || selected.RecordedAt.Before(start.Add(-maxStartDrift(days))) {
+		return baseline{}, errNoHistory
+	}
+	return snapshotBaseline(SourceRollups, selected.RecordedAt, selected.Session), nil
+}
+
+func snapshotBaseline(source Source, start time.Time, session core.SessionSnapshot) baseline {
+	b := baseline{Source: source, Start: start, Vehicles: make(map[int]core.ReducedStatsFrame)}
+	for id, vehicle := range session.Vehicles {
+		if vehicle.ReducedStatsFrame != nil {
+			b.Vehicles[id] = *vehicle.ReducedStatsFrame
+		}
+	}
+	return b
+}
+
+/*
+blitzStarsHistory selects the entry with most battles played before start for each vehicle
+*/
+func blitzStarsHistory(accountID int, start time.Time, _ int) (baseline, error) {
+	tankHistory, err := blitzstars.GetPlayerTankHistories(accountID)
+	if err != nil {
+		return baseline{}, err
+	}
+
+	b := baseline{Source: SourceBlitzStars, Start: start, Vehicles: make(map[int]core.ReducedStatsFrame)}
+	for id, entries := range tankHistory {
+		// Sort entries by number of battles in descending order
+		slices.SortFunc(entries, func(i, j blitzstars.TankHistoryEntry) int {
+			return j.Stats.Battles - i.Stats.Battles
+		})
+
+		for _, entry := range entries {
+			if entry.LastBattleTime < int(start.Unix()) {
+				b.Vehicles[id] = stats.FrameToReducedStatsFrame(entry.Stats)
+				break
+			}
+		}
+	}
+	return b, nil
+}
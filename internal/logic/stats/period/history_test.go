@@ -0,0 +1,71 @@
+package period
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	core "github.com/cufee/aftermath-core/internal/core/stats"
+)
+
+func TestStoredHistory(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = memory.NewStorage()
+
+	const accountID = 1013072123
+	start := time.Now().Add(-durationDay * 15)
+	session := core.SessionSnapshot{
+		AccountID:      accountID,
+		LastBattleTime: int(start.Unix()),
+		Vehicles:       map[int]core.ReducedVehicleStats{1: {VehicleID: 1, ReducedStatsFrame: &core.ReducedStatsFrame{Battles: 10}}},
+	}
+
+	// Snapshots recorded after start cannot be used as a baseline
+	err := database.InsertSession(models.SessionTypeDaily, nil, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = snapshotHistory(accountID, start, 15)
+	if !errors.Is(err, errNoHistory) {
+		t.Errorf("expected errNoHistory, got %v", err)
+	}
+
+	weekly := models.SessionRollup{
+		Period:      models.RollupPeriodWeekly,
+		PeriodStart: models.RollupPeriodWeekly.Start(start).Add(-durationDay * 7),
+		RecordedAt:  start.Add(-durationDay * 2),
+		ExpiresAt:   time.Now().Add(durationDay),
+		Session:     session,
+	}
+	monthly := weekly
+	monthly.Period = models.RollupPeriodMonthly
+	monthly.PeriodStart = models.RollupPeriodMonthly.Start(start)
+	monthly.RecordedAt = start.Add(-durationDay * 10)
+	// The current week started before start, but its snapshot was only recorded after it
+	late := weekly
+	late.PeriodStart = models.RollupPeriodWeekly.Start(start)
+	late.RecordedAt = start.Add(time.Hour)
+	err = database.UpsertSessionRollups(weekly, monthly, late)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The closest rollup is selected and its exact start is reported
+	history, err := rollupHistory(accountID, start, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if history.Source != SourceRollups || !history.Start.Equal(weekly.RecordedAt) || history.Vehicles[1].Battles != 10 {
+		t.Errorf("unexpected baseline %+v", history)
+	}
+
+	// A 7 day period only allows a baseline one and a half days earlier than requested
+	_, err = rollupHistory(accountID, start, 7)
+	if !errors.Is(err, errNoHistory) {
+		t.Errorf("expected errNoHistory, got %v", err)
+	}
+}
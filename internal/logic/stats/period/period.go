@@ -1,27 +1,25 @@
 package period
 
 import (
-	"slices"
-	"strings"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/database"
 	core "github.com/cufee/aftermath-core/internal/core/stats"
 	"github.com/cufee/aftermath-core/internal/core/wargaming"
-	"github.com/cufee/aftermath-core/internal/logic/external/blitzstars"
+	"github.com/cufee/aftermath-core/internal/logic/scheduler/schedule"
 	"github.com/cufee/aftermath-core/internal/logic/stats"
 
 	"github.com/cufee/am-wg-proxy-next/v2/types"
 	"github.com/cufee/am-wg-proxy-next/v2/utils"
-	"github.com/gorhill/cronexpr"
 )
 
 type PeriodStats struct {
 	Account types.Account `json:"account"`
 	Clan    types.Clan    `json:"clan"`
 
-	Start time.Time `json:"start"`
-	End   time.Time `json:"end"`
+	Source Source    `json:"source"`
+	Start  time.Time `json:"start"` // exact start of the selected baseline, can be earlier than requested
+	End    time.Time `json:"end"`
 
 	Vehicles map[int]core.ReducedVehicleStats `json:"vehicles"`
 	Stats    core.ReducedStatsFrame           `json:"stats"`
@@ -32,34 +30,20 @@ func (stats *PeriodStats) CareerWN8(averages map[int]core.ReducedStatsFrame) int
 		return v
 	}
 
-	var weightedWN8Total, wn8BattlesTotal int
-	for id, vehicle := range stats.Vehicles {
-		if vehicle.Battles < 1 {
-			continue
-		}
-		if data, ok := averages[id]; ok {
-			weightedWN8Total += vehicle.Battles * vehicle.WN8(data)
-			wn8BattlesTotal += vehicle.Battles
-		}
-	}
-	if wn8BattlesTotal < 1 {
-		return core.InvalidValueInt
+	v, _ := core.WeightedWN8(stats.Vehicles, averages)
+	if v == core.InvalidValueInt {
+		return v
 	}
 
-	v := weightedWN8Total / wn8BattlesTotal
 	stats.Stats.SetWN8(v)
 	return v
 }
 
 const durationDay = time.Hour * 24
 
-var sessionsCronNA = cronexpr.MustParse("0 9 * * *")
-var sessionsCronEU = cronexpr.MustParse("0 1 * * *")
-var sessionsCronAsia = cronexpr.MustParse("0 18 * * *")
-
-func GetPlayerStats(accountId int, days int) (PeriodStats, error) {
+func GetPlayerStats(client wargaming.StatsProvider, accountId int, days int) (PeriodStats, error) {
 	realm := utils.RealmFromPlayerID(accountId)
-	allStats, err := stats.GetCompleteStatsWithClient(wargaming.Clients.Live, realm, accountId)
+	allStats, err := stats.GetCompleteStatsWithClient(client, realm, accountId)
 	if err != nil {
 		return PeriodStats{}, err
 	}
@@ -105,33 +89,31 @@ func GetPlayerStats(accountId int, days int) (PeriodStats, error) {
 			periodStats.Vehicles[vehicle.TankID] = stats
 		}
 
-		// TODO: Get calculated career WN8
-
+		periodStats.Source = SourceCareer
 		periodStats.Start = time.Unix(int64(accountStats.Data.Account.CreatedAt), 0)
 		periodStats.Stats = accountStats.Data.Session.Global
 
+		// Use the career WN8 calculated by the background task if the account did not play since
+		if account, err := database.GetPlayerAccount(accountId); err == nil && account.WN8 != nil && account.WN8.LastBattleTime == accountStats.Data.Account.LastBattleTime {
+			periodStats.Stats.SetWN8(account.WN8.Value)
+		}
+
 		periodStats.CareerWN8(tankAverages)
 		return periodStats, nil
 
 	default:
 		// Get time specific stats
 		periodStats.Start = daysToRealmTime(realm, days)
-		if periodStats.End.Before(periodStats.Start) {
-			periodStats.End = time.Now()
-		}
 	}
 
-	tankHistory, err := blitzstars.GetPlayerTankHistories(accountId)
+	history, err := getBaseline(accountId, periodStats.Start, days)
 	if err != nil {
 		return PeriodStats{}, err
 	}
-
-	var vehiclesMap = make(map[int]types.VehicleStatsFrame)
-	for _, vehicle := range accountStats.Data.Vehicles {
-		if vehicle.LastBattleTime < int(periodStats.Start.Unix()) {
-			continue
-		}
-		vehiclesMap[vehicle.TankID] = vehicle
+	periodStats.Source = history.Source
+	periodStats.Start = history.Start
+	if periodStats.End.Before(periodStats.Start) {
+		periodStats.End = time.Now()
 	}
 
 	for _, vehicle := range accountStats.Data.Vehicles {
@@ -139,22 +121,8 @@ func GetPlayerStats(accountId int, days int) (PeriodStats, error) {
 			continue
 		}
 
-		entries := tankHistory[vehicle.TankID]
-		// Sort entries by number of battles in descending order
-		slices.SortFunc(entries, func(i, j blitzstars.TankHistoryEntry) int {
-			return j.Stats.Battles - i.Stats.Battles
-		})
-
-		var selectedEntry blitzstars.TankHistoryEntry
-		for _, entry := range entries {
-			if entry.LastBattleTime < int(periodStats.Start.Unix()) {
-				selectedEntry = entry
-				break
-			}
-		}
-
-		if selectedEntry.Stats.Battles < vehicle.Stats.Battles {
-			selectedFrame := stats.FrameToReducedStatsFrame(selectedEntry.Stats)
+		selectedFrame := history.Vehicles[vehicle.TankID]
+		if selectedFrame.Battles < vehicle.Stats.Battles {
 			compareToFrame := stats.FrameToReducedStatsFrame(vehicle.Stats)
 			compareToFrame.Subtract(selectedFrame)
 
@@ -177,14 +145,9 @@ func GetPlayerStats(accountId int, days int) (PeriodStats, error) {
 func daysToRealmTime(realm string, days int) time.Time {
 	duration := durationDay * time.Duration(days)
 
-	switch strings.ToLower(realm) {
-	case "na":
-		return sessionsCronNA.Next(time.Now()).Add(durationDay * -1).Add(-duration)
-	case "eu":
-		return sessionsCronEU.Next(time.Now()).Add(durationDay * -1).Add(-duration)
-	case "as":
-		return sessionsCronAsia.Next(time.Now()).Add(durationDay * -1).Add(-duration)
-	default:
+	nextReset, err := schedule.Next(schedule.SessionReset(realm), time.Now())
+	if err != nil {
 		return time.Now()
 	}
+	return nextReset.Add(durationDay * -1).Add(-duration)
 }
@@ -1,19 +1,77 @@
 package period
 
 import (
-	"encoding/json"
-	"fmt"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	core "github.com/cufee/aftermath-core/internal/core/stats"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/am-wg-proxy-next/v2/types"
+	"github.com/cufee/am-wg-proxy-next/v2/utils"
 )
 
 func TestGetPlayerStats(t *testing.T) {
-	stats, err := GetPlayerStats(1013072123, 15)
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = memory.NewStorage()
+
+	const accountID = 1013072123
+	now := int(time.Now().Unix())
+	provider := wargaming.NewFixtureProvider()
+	account := types.ExtendedAccount{Account: types.Account{ID: accountID, Nickname: "Fixture"}, CreatedAt: 1500000000, LastBattleTime: now}
+	account.Statistics.All = types.StatsFrame{Battles: 160, Wins: 90}
+	provider.AddAccount(account, []types.VehicleStatsFrame{
+		{TankID: 1, Stats: types.StatsFrame{Battles: 110, Wins: 60}, LastBattleTime: now},
+		{TankID: 2, Stats: types.StatsFrame{Battles: 50, Wins: 30}, LastBattleTime: 1600000000},
+	}, types.ClanMember{})
+
+	// Career stats are returned as is
+	career, err := GetPlayerStats(provider, accountID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if career.Source != SourceCareer || career.Stats.Battles != 160 || len(career.Vehicles) != 2 {
+		t.Errorf("unexpected career stats %+v", career)
+	}
+
+	// Periods are diffed against the closest stored baseline, vehicles without new battles are skipped
+	start := daysToRealmTime(utils.RealmFromPlayerID(accountID), 15)
+	recordedAt := start.Add(-time.Hour)
+	err = database.UpsertSessionRollups(models.SessionRollup{
+		Period:      models.RollupPeriodWeekly,
+		PeriodStart: models.RollupPeriodWeekly.Start(recordedAt),
+		RecordedAt:  recordedAt,
+		ExpiresAt:   time.Now().Add(durationDay),
+		Session: core.SessionSnapshot{
+			AccountID: accountID,
+			Vehicles: map[int]core.ReducedVehicleStats{
+				1: {VehicleID: 1, ReducedStatsFrame: &core.ReducedStatsFrame{Battles: 100, BattlesWon: 55}},
+				2: {VehicleID: 2, ReducedStatsFrame: &core.ReducedStatsFrame{Battles: 50, BattlesWon: 30}},
+			},
+		},
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// stats.Vehicles = nil
+	period, err := GetPlayerStats(provider, accountID, 15)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if period.Source != SourceRollups || !period.Start.Equal(recordedAt) {
+		t.Errorf("unexpected baseline %s at %s", period.Source, period.Start)
+	}
+	if period.Stats.Battles != 10 || period.Stats.BattlesWon != 5 || len(period.Vehicles) != 1 {
+		t.Errorf("unexpected period stats %+v", period)
+	}
 
-	data, _ := json.MarshalIndent(stats, "", "  ")
-	fmt.Println(string(data))
+	// Provider errors are returned
+	_, err = GetPlayerStats(&wargaming.FixtureProvider{Err: wargaming.ErrProviderNotConfigured}, accountID, 15)
+	if !errors.Is(err, wargaming.ErrProviderNotConfigured) {
+		t.Errorf("expected ErrProviderNotConfigured, got %v", err)
+	}
 }
@@ -5,13 +5,13 @@ import (
 	"sync"
 
 	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/stats"
-	"github.com/cufee/am-wg-proxy-next/v2/remote"
 	wg "github.com/cufee/am-wg-proxy-next/v2/types"
 	"github.com/rs/zerolog/log"
 )
 
-func GetAccountInfo(client *remote.Client, realm string, accountID int) (*stats.AccountWithClan, error) {
+func GetAccountInfo(client wargaming.StatsProvider, realm string, accountID int) (*stats.AccountWithClan, error) {
 	var waitGroup sync.WaitGroup
 
 	accountStr := fmt.Sprintf("%d", accountID)
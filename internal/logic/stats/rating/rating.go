@@ -0,0 +1,160 @@
+package rating
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
+	"github.com/cufee/aftermath-core/internal/logic/stats"
+	wgUtils "github.com/cufee/am-wg-proxy-next/v2/utils"
+	"github.com/rs/zerolog/log"
+
+	wg "github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+type SeasonProgress struct {
+	SeasonID int              `json:"seasonId"`
+	StartAt  time.Time        `json:"startAt"`
+	FinishAt time.Time        `json:"finishAt"`
+	League   *wotblitz.League `json:"league,omitempty"`
+
+	Current models.RatingSnapshot   `json:"current"`
+	Start   *models.RatingSnapshot  `json:"start,omitempty"` // First snapshot recorded during this season
+	Change  *SeasonChange           `json:"change,omitempty"`
+	History []models.RatingSnapshot `json:"history"`
+}
+
+type SeasonChange struct {
+	Battles    int `json:"battles"`
+	BattlesWon int `json:"battlesWon"`
+	Score      int `json:"score"`
+	Position   int `json:"position"` // Positive values mean that the player moved up on the leaderboard
+}
+
+/*
+NewSnapshot creates a rating snapshot from account stats and an optional leaderboard position.
+*/
+func NewSnapshot(realm string, seasonID int, account wg.ExtendedAccount, position *wotblitz.PlayerLeaderboard) models.RatingSnapshot {
+	snapshot := models.RatingSnapshot{
+		CreatedAt:      time.Now(),
+		Realm:          strings.ToUpper(realm),
+		SeasonID:       seasonID,
+		AccountID:      account.ID,
+		LastBattleTime: account.LastBattleTime,
+		Stats:          stats.FrameToReducedStatsFrame(account.Statistics.Rating),
+	}
+	if position != nil {
+		snapshot.Score = position.Score
+		snapshot.Position = position.Position
+		snapshot.Percentile = position.Percentile
+		snapshot.LeagueIndex = position.LeagueIndex
+		snapshot.CalibrationBattlesLeft = position.CalibrationBattlesLeft
+	}
+	return snapshot
+}
+
+/*
+GetSeasonProgress returns live rating stats for the current season along with all snapshots recorded during this season.
+  - Leaderboard errors are not fatal, position related fields will be blank
+*/
+func GetSeasonProgress(client wargaming.StatsProvider, accountId int) (*SeasonProgress, error) {
+	realm := wgUtils.RealmFromPlayerID(accountId)
+
+	season, err := wotblitz.GetCurrentRatingSeason(realm)
+	if err != nil {
+		return nil, err
+	}
+
+	accountStr := fmt.Sprint(accountId)
+	accounts, err := client.BulkGetAccountsByID([]string{accountStr}, realm)
+	if err != nil {
+		return nil, err
+	}
+	account, ok := accounts[accountStr]
+	if !ok || account.ID == 0 {
+		return nil, stats.ErrBlankResponse
+	}
+
+	position, err := wotblitz.GetPlayerRatingPosition(accountId, 0)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to get player rating position")
+		position = nil
+	}
+
+	history, err := database.GetRatingSnapshots(accountId, season.SeasonID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := SeasonProgress{
+		SeasonID: season.SeasonID,
+		StartAt:  season.Start(),
+		FinishAt: season.Finish(),
+		Current:  NewSnapshot(realm, season.SeasonID, account, position),
+		History:  history,
+	}
+	if position != nil {
+		if league, ok := season.League(position.LeagueIndex); ok {
+			progress.League = &league
+		}
+	}
+	if len(history) > 0 {
+		progress.Start = &history[0]
+		progress.Change = &SeasonChange{
+			Battles:    progress.Current.Stats.Battles - history[0].Stats.Battles,
+			BattlesWon: progress.Current.Stats.BattlesWon - history[0].Stats.BattlesWon,
+			Score:      progress.Current.Score - history[0].Score,
+		}
+		if progress.Current.Position > 0 && history[0].Position > 0 {
+			progress.Change.Position = history[0].Position - progress.Current.Position
+		}
+	}
+
+	return &progress, nil
+}
+
+/*
+GetSeasonHistory returns all snapshots recorded for an account during a season.
+*/
+func GetSeasonHistory(accountId, seasonId int) ([]models.RatingSnapshot, error) {
+	return database.GetRatingSnapshots(accountId, seasonId)
+}
+
+/*
+GetSeasonResults returns the final snapshot of each finished season for an account.
+*/
+func GetSeasonResults(accountId int) ([]models.RatingSnapshot, error) {
+	return database.GetFinalRatingSnapshots(accountId)
+}
+
+/*
+GetLeaderboardPosition returns the player position on the current season leaderboard along with the season details.
+The season is optional and nil when it could not be fetched, the position is still returned so that the overview can be rendered without a league.
+*/
+func GetLeaderboardPosition(accountId int, neighbors int) (*wotblitz.PlayerLeaderboard, *wotblitz.RatingSeason, error) {
+	var waitGroup sync.WaitGroup
+	var season utils.DataWithError[*wotblitz.RatingSeason]
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+		season.Data, season.Err = wotblitz.GetCurrentRatingSeason(wgUtils.RealmFromPlayerID(accountId))
+	}()
+
+	position, err := wotblitz.GetPlayerRatingPosition(accountId, neighbors)
+	waitGroup.Wait()
+	if err != nil {
+		return nil, nil, err
+	}
+	if season.Err != nil {
+		log.Warn().Err(season.Err).Int("accountId", accountId).Msg("failed to get current rating season")
+		return position, nil, nil
+	}
+	return position, season.Data, nil
+}
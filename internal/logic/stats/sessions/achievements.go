@@ -0,0 +1,79 @@
+package sessions
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/logic/external/wotblitz"
+	"github.com/cufee/am-wg-proxy-next/v2/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const achievementsCacheTTL = time.Hour * 24
+
+type cachedAchievements struct {
+	snapshotID     primitive.ObjectID
+	lastBattleTime int
+	earned         map[string]int
+	expiresAt      time.Time
+}
+
+/*
+achievementsCache holds the last diff for each account, it is valid until the account plays another battle or a new snapshot is recorded
+*/
+var achievementsCache = struct {
+	mu       sync.Mutex
+	accounts map[achievementsKey]cachedAchievements
+}{accounts: make(map[achievementsKey]cachedAchievements)}
+
+type achievementsKey struct {
+	accountID   int
+	referenceID string
+}
+
+/*
+GetAchievementsSinceSnapshot returns achievements earned by an account since the last achievements snapshot with referenceID was recorded, an empty reference uses realm-wide snapshots.
+Achievements can only change after a battle, live data is only requested when lastBattleTime is newer than the snapshot and the cached diff.
+*/
+func GetAchievementsSinceSnapshot(accountId int, referenceID string, lastBattleTime int) (map[string]int, error) {
+	key := achievementsKey{accountID: accountId, referenceID: referenceID}
+	lastSnapshot, err := database.GetLastAchievementsSnapshot(accountId, referenceID)
+	if err != nil {
+		return nil, err
+	}
+	if int64(lastBattleTime) <= lastSnapshot.CreatedAt.Unix() {
+		return map[string]int{}, nil
+	}
+
+	achievementsCache.mu.Lock()
+	cached, ok := achievementsCache.accounts[key]
+	achievementsCache.mu.Unlock()
+	if ok && cached.snapshotID == lastSnapshot.ID && cached.lastBattleTime == lastBattleTime && time.Now().Before(cached.expiresAt) {
+		return cached.earned, nil
+	}
+
+	live, err := wotblitz.GetAccountsAchievements(utils.RealmFromPlayerID(accountId), accountId)
+	if err != nil {
+		return nil, err
+	}
+
+	current := models.AchievementsSnapshot{AccountID: accountId, Achievements: live[accountId].Achievements}
+	earned := current.Diff(lastSnapshot)
+	cacheAchievements(key, cachedAchievements{snapshotID: lastSnapshot.ID, lastBattleTime: lastBattleTime, earned: earned, expiresAt: time.Now().Add(achievementsCacheTTL)})
+	return earned, nil
+}
+
+func cacheAchievements(key achievementsKey, entry cachedAchievements) {
+	achievementsCache.mu.Lock()
+	defer achievementsCache.mu.Unlock()
+
+	now := time.Now()
+	for id, cached := range achievementsCache.accounts {
+		if now.After(cached.expiresAt) {
+			delete(achievementsCache.accounts, id)
+		}
+	}
+	achievementsCache.accounts[key] = entry
+}
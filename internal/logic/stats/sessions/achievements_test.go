@@ -0,0 +1,47 @@
+package sessions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+)
+
+func TestAchievementsWithoutLiveData(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = memory.NewStorage()
+
+	const accountID = 1013072123
+	created := time.Now().Add(-time.Hour)
+	err := database.InsertAchievementsSnapshots(models.AchievementsSnapshot{AccountID: accountID, Achievements: map[string]int{"medalKay": 1}, CreatedAt: created})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := database.GetLastAchievementsSnapshot(accountID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No battles were played since the snapshot, there is nothing to request
+	earned, err := GetAchievementsSinceSnapshot(accountID, "", int(created.Add(-time.Minute).Unix()))
+	if err != nil || len(earned) != 0 {
+		t.Errorf("expected no achievements, got %v %v", earned, err)
+	}
+
+	// Users with a custom reset are only compared to snapshots from their reset
+	_, err = GetAchievementsSinceSnapshot(accountID, "reset/UTC/05", int(time.Now().Unix()))
+	if err != database.ErrNoAchievementsSnapshot {
+		t.Errorf("expected ErrNoAchievementsSnapshot, got %v", err)
+	}
+
+	// The diff is reused until the account plays another battle
+	lastBattle := int(time.Now().Unix())
+	cacheAchievements(achievementsKey{accountID: accountID}, cachedAchievements{snapshotID: snapshot.ID, lastBattleTime: lastBattle, earned: map[string]int{"medalKay": 1}, expiresAt: time.Now().Add(time.Minute)})
+	earned, err = GetAchievementsSinceSnapshot(accountID, "", lastBattle)
+	if err != nil || earned["medalKay"] != 1 {
+		t.Errorf("expected cached achievements, got %v %v", earned, err)
+	}
+}
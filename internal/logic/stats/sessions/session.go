@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
 	core "github.com/cufee/aftermath-core/internal/core/stats"
 	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/am-wg-proxy-next/v2/utils"
@@ -12,7 +13,22 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func GetCurrentPlayerSession(accountId int, options ...database.SessionGetOptions) (Snapshot, error) {
+/*
+WithSessionReset updates daily session options to select snapshots recorded at a custom reset time, GetCurrentPlayerSession falls back to the realm-wide session.
+Options with a reference ID or a different session type are returned unchanged.
+*/
+func WithSessionReset(opts database.SessionGetOptions, reset *models.SessionReset) database.SessionGetOptions {
+	if reset == nil || opts.ReferenceID != nil || (opts.Type != "" && opts.Type != models.SessionTypeDaily) {
+		return opts
+	}
+
+	reference := reset.Reference()
+	opts.Type = models.SessionTypeReset
+	opts.ReferenceID = &reference
+	return opts
+}
+
+func GetCurrentPlayerSession(client wargaming.StatsProvider, accountId int, options ...database.SessionGetOptions) (Snapshot, error) {
 	opts := database.SessionGetOptions{}
 	if len(options) > 0 {
 		opts = options[0]
@@ -20,7 +36,7 @@ func GetCurrentPlayerSession(accountId int, options ...database.SessionGetOption
 
 	var snapshot Snapshot
 
-	liveSessions, err := stats.GetCompleteStatsWithClient(wargaming.Clients.Live, utils.RealmFromPlayerID(accountId), accountId)
+	liveSessions, err := stats.GetCompleteStatsWithClient(client, utils.RealmFromPlayerID(accountId), accountId)
 	if err != nil {
 		log.Err(err).Msg("failed to get live sessions")
 		return snapshot, err
@@ -46,6 +62,11 @@ func GetCurrentPlayerSession(accountId int, options ...database.SessionGetOption
 	}
 
 	lastSession, err := database.GetPlayerSessionSnapshot(accountId, opts)
+	if errors.Is(err, database.ErrNoSessionCache) && opts.Type == models.SessionTypeReset {
+		// Nothing was recorded at the user's reset time yet, fall back to the realm-wide daily session
+		opts.Type, opts.ReferenceID = models.SessionTypeDaily, nil
+		lastSession, err = database.GetPlayerSessionSnapshot(accountId, opts)
+	}
 	if errors.Is(err, database.ErrNoSessionCache) {
 		// There is no session cache, so the live session is the same as the last session and there is no diff
 		snapshot.Diff = core.EmptySession(liveSession.Data.Account.ID, liveSession.Data.Account.LastBattleTime)
@@ -0,0 +1,69 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cufee/aftermath-core/internal/core/database"
+	"github.com/cufee/aftermath-core/internal/core/database/memory"
+	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	"github.com/cufee/aftermath-core/internal/logic/stats"
+	"github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+func TestGetCurrentPlayerSession(t *testing.T) {
+	previous := database.DefaultStorage
+	t.Cleanup(func() { database.DefaultStorage = previous })
+	database.DefaultStorage = memory.NewStorage()
+
+	const accountID = 1013072123
+	provider := wargaming.NewFixtureProvider()
+	addAccount := func(battles, lastBattleTime int) {
+		account := types.ExtendedAccount{Account: types.Account{ID: accountID, Nickname: "Fixture"}, LastBattleTime: lastBattleTime}
+		account.Statistics.All = types.StatsFrame{Battles: battles + 50}
+		provider.AddAccount(account, []types.VehicleStatsFrame{
+			{TankID: 1, Stats: types.StatsFrame{Battles: battles}, LastBattleTime: lastBattleTime},
+			{TankID: 2, Stats: types.StatsFrame{Battles: 50}, LastBattleTime: 1600000000},
+		}, types.ClanMember{})
+	}
+	addAccount(100, 1700000000)
+
+	// Without a cached session the live session is selected and there is no diff
+	snapshot, err := GetCurrentPlayerSession(provider, accountID)
+	if !errors.Is(err, ErrNoSessionCached) {
+		t.Fatalf("expected ErrNoSessionCached, got %v", err)
+	}
+	if snapshot.Selected.Global.Battles != 150 || snapshot.Diff.Global.Battles != 0 {
+		t.Errorf("unexpected session %+v", snapshot)
+	}
+
+	complete, err := stats.GetCompleteStatsWithClient(provider, "NA", accountID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.InsertSession(models.SessionTypeDaily, nil, complete[accountID].Data.Session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addAccount(110, 1700003600)
+
+	// Users with a custom reset fall back to the realm-wide session until one is recorded at their reset
+	reset := &models.SessionReset{Hour: 4, Timezone: "UTC"}
+	snapshot, err = GetCurrentPlayerSession(provider, accountID, WithSessionReset(database.SessionGetOptions{}, reset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Diff.Global.Battles != 10 || snapshot.Selected.Global.Battles != 150 {
+		t.Errorf("unexpected session %+v", snapshot)
+	}
+	if _, ok := snapshot.Diff.Vehicles[2]; ok || snapshot.Diff.Vehicles[1].Battles != 10 {
+		t.Errorf("expected only vehicles played during the session, got %+v", snapshot.Diff.Vehicles)
+	}
+
+	// Provider errors are returned
+	_, err = GetCurrentPlayerSession(&wargaming.FixtureProvider{Err: wargaming.ErrProviderNotConfigured}, accountID)
+	if !errors.Is(err, wargaming.ErrProviderNotConfigured) {
+		t.Errorf("expected ErrProviderNotConfigured, got %v", err)
+	}
+}
@@ -10,7 +10,6 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/rs/zerolog/log"
 
-	"github.com/cufee/am-wg-proxy-next/v2/remote"
 	wg "github.com/cufee/am-wg-proxy-next/v2/types"
 )
 
@@ -31,7 +30,7 @@ var (
 	ErrTooManyAccountIDs = errors.New("too many account IDs")
 )
 
-func GetLastBattleTimes(realm string, accountIDs ...int) (map[int]int, error) {
+func GetLastBattleTimes(client wargaming.StatsProvider, realm string, accountIDs ...int) (map[int]int, error) {
 	if len(accountIDs) == 0 {
 		return make(map[int]int), nil
 	}
@@ -44,7 +43,7 @@ func GetLastBattleTimes(realm string, accountIDs ...int) (map[int]int, error) {
 		ids[i] = fmt.Sprintf("%d", id)
 	}
 
-	players, err := wargaming.Clients.Live.BulkGetAccountsByID(ids, realm, "account_id", "last_battle_time")
+	players, err := client.BulkGetAccountsByID(ids, realm, "account_id", "last_battle_time")
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +56,7 @@ func GetLastBattleTimes(realm string, accountIDs ...int) (map[int]int, error) {
 	return lastBattleTimes, nil
 }
 
-func GetCompleteStatsWithClient(client *remote.Client, realm string, accountIDs ...int) (map[int]utils.DataWithError[*CompleteStats], error) {
+func GetCompleteStatsWithClient(client wargaming.StatsProvider, realm string, accountIDs ...int) (map[int]utils.DataWithError[*CompleteStats], error) {
 	if len(accountIDs) > 100 {
 		return nil, ErrTooManyAccountIDs
 	}
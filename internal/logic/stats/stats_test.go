@@ -0,0 +1,36 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
+	wg "github.com/cufee/am-wg-proxy-next/v2/types"
+)
+
+func TestGetCompleteStatsWithFixture(t *testing.T) {
+	provider := wargaming.NewFixtureProvider()
+
+	account := wg.ExtendedAccount{Account: wg.Account{ID: 1013072123, Nickname: "fixture"}, LastBattleTime: 1700000000}
+	account.Statistics.All.Battles = 100
+	provider.AddAccount(account, []wg.VehicleStatsFrame{{TankID: 1, Stats: wg.StatsFrame{Battles: 100}}}, wg.ClanMember{ClanID: 10, Clan: wg.Clan{ID: 10, Tag: "TAG"}})
+
+	result, err := GetCompleteStatsWithClient(provider, "eu", account.ID, 1013072124)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := result[account.ID]
+	if stats.Err != nil {
+		t.Fatal(stats.Err)
+	}
+	if stats.Data.Session.Global.Battles != 100 || len(stats.Data.Session.Vehicles) != 1 {
+		t.Errorf("unexpected session snapshot: %+v", stats.Data.Session)
+	}
+	if stats.Data.Clan.Clan.Tag != "TAG" {
+		t.Errorf("expected clan TAG, got %q", stats.Data.Clan.Clan.Tag)
+	}
+
+	if result[1013072124].Err == nil {
+		t.Error("expected an error for an account missing from the provider")
+	}
+}
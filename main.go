@@ -5,7 +5,9 @@ import (
 
 	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/scheduler"
+	"github.com/cufee/aftermath-core/internal/logic/scheduler/schedule"
 	"github.com/cufee/aftermath-core/internal/logic/server"
 	"github.com/rs/zerolog"
 )
@@ -25,6 +27,20 @@ func main() {
 		}
 	}
 
+	if err := database.BackfillDefaultConnections(database.DefaultClient.Database()); err != nil {
+		panic(err)
+	}
+
+	err = wargaming.LoadClients()
+	if err != nil {
+		panic(err)
+	}
+
+	// Schedules are also used outside of the scheduler to find session reset times
+	if err := schedule.Load(); err != nil {
+		panic(err)
+	}
+
 	if os.Getenv("SCHEDULER_ENABLED") != "false" {
 		scheduler.StartCronJobs()
 	}
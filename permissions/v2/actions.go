@@ -50,6 +50,8 @@ const (
 const (
 	// Admin
 	ManageUserRoles Permissions = 1 << (45 + iota)
+	RetrieveAuditLog
+	ManageTasks
 )
 
 func init() {
@@ -87,4 +89,6 @@ func init() {
 	PermissionsMap["actions/removeUserRestriction"] = RemoveUserRestriction
 
 	PermissionsMap["actions/manageUserRoles"] = ManageUserRoles
+	PermissionsMap["actions/retrieveAuditLog"] = RetrieveAuditLog
+	PermissionsMap["actions/manageTasks"] = ManageTasks
 }
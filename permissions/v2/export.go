@@ -1,3 +1,30 @@
 package permissions
 
+import (
+	"slices"
+	"strings"
+)
+
 var PermissionsMap = make(map[string]Permissions)
+
+/*
+Lookup returns permissions by a PermissionsMap key, for example roles/admin or actions/useLiveSessions
+*/
+func Lookup(name string) (Permissions, bool) {
+	perms, ok := PermissionsMap[name]
+	return perms, ok
+}
+
+/*
+Names returns sorted PermissionsMap keys with a prefix that are fully included in p
+*/
+func (p Permissions) Names(prefix string) []string {
+	var names []string
+	for name, perms := range PermissionsMap {
+		if strings.HasPrefix(name, prefix) && perms != Blank && p.Has(perms) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -6,7 +6,7 @@ const (
 	ContentModerator = User | UpdateUserBackground | RemoveUserBackground | CreateUserRestriction
 	GlobalModerator  = ContentModerator | RetrieveUserSubscriptions | CreateUserSubscription | ExtendUserSubscription | TerminateUserSubscription | UploadBackgroundPreset | RemoveBackgroundPreset | RetrieveUserConnections | ManageUserConnectionVerification | RemoveUserConnection | RetrieveUserRestrictions | RemoveUserRestriction
 
-	Admin = GlobalModerator | ManageUserRoles
+	Admin = GlobalModerator | ManageUserRoles | RetrieveAuditLog | ManageTasks
 )
 
 func init() {
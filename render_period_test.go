@@ -9,6 +9,7 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/database"
 	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	core "github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/render/assets"
 	render "github.com/cufee/aftermath-core/internal/logic/render/period"
@@ -21,8 +22,12 @@ func TestFullPeriodRenderPipeline(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	err = wargaming.LoadClients()
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	stats, err := period.GetPlayerStats(521493973, 1)
+	stats, err := period.GetPlayerStats(wargaming.Clients.Live, 521493973, 1)
 	if err != nil {
 		t.Fatal(err)
 	}
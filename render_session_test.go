@@ -13,6 +13,7 @@ import (
 	"github.com/cufee/aftermath-core/internal/core/database/models"
 	"github.com/cufee/aftermath-core/internal/core/localization"
 	"github.com/cufee/aftermath-core/internal/core/utils"
+	"github.com/cufee/aftermath-core/internal/core/wargaming"
 	"github.com/cufee/aftermath-core/internal/logic/render"
 	"github.com/cufee/aftermath-core/internal/logic/render/assets"
 	"github.com/cufee/aftermath-core/internal/logic/render/session"
@@ -28,8 +29,12 @@ func TestFullSessionRenderPipeline(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	err = wargaming.LoadClients()
+	if err != nil {
+		t.Fatal(err)
+	}
 
-	sessionData, err := sessions.GetCurrentPlayerSession(1013072123) // 1013072123 1032698345 521493973
+	sessionData, err := sessions.GetCurrentPlayerSession(wargaming.Clients.Live, 1013072123) // 1013072123 1032698345 521493973
 	if err != nil && !errors.Is(err, sessions.ErrNoSessionCached) {
 		t.Fatal(err)
 	}
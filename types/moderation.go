@@ -1,9 +1,11 @@
 package types
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/cufee/aftermath-core/internal/core/database/models"
+	"github.com/cufee/aftermath-core/permissions/v2"
 )
 
 type SubscriptionPayload struct {
@@ -56,10 +58,98 @@ type ForceUpdateConnectionPayload struct {
 }
 
 func (update *ForceUpdateConnectionPayload) Type() (models.ConnectionType, bool) {
-	switch update.ConnectionType {
-	case string(models.ConnectionTypeWargaming):
-		return models.ConnectionTypeWargaming, true
-	default:
-		return "", false
+	return models.ParseConnectionType(update.ConnectionType)
+}
+
+type RestrictionPayload struct {
+	UserID      string    `json:"userID"`
+	Scopes      []string  `json:"scopes"`
+	UserMessage string    `json:"userMessage"`
+	Comment     string    `json:"comment"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+func (r RestrictionPayload) Valid() bool {
+	return r.UserID != "" && !r.ExpiresAt.IsZero() && models.ValidRestrictionScopes(r.Scopes)
+}
+
+func (r RestrictionPayload) ToUserRestriction(createdBy string) (models.UserRestriction, bool) {
+	if !r.Valid() {
+		return models.UserRestriction{}, false
+	}
+
+	return models.UserRestriction{
+		UserID:      r.UserID,
+		CreatedBy:   createdBy,
+		Scopes:      r.Scopes,
+		UserMessage: r.UserMessage,
+		Comment:     r.Comment,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   r.ExpiresAt,
+	}, true
+}
+
+func (r *RestrictionPayload) ToRestrictionUpdate() (models.RestrictionUpdate, bool) {
+	var update models.RestrictionUpdate
+	if r.Scopes != nil {
+		if !models.ValidRestrictionScopes(r.Scopes) {
+			return update, false
+		}
+		update.Scopes = &r.Scopes
+	}
+	if r.UserMessage != "" {
+		update.UserMessage = &r.UserMessage
+	}
+	if r.Comment != "" {
+		update.Comment = &r.Comment
+	}
+	if !r.ExpiresAt.IsZero() {
+		update.ExpiresAt = &r.ExpiresAt
+	}
+	return update, true
+}
+
+/*
+UserPermissionsPayload grants and revokes permissions stored on a user, entries are PermissionsMap keys such as roles/contentModerator or actions/uploadBackgroundPreset
+*/
+type UserPermissionsPayload struct {
+	Grant  []string `json:"grant"`
+	Revoke []string `json:"revoke"`
+}
+
+func (p UserPermissionsPayload) Apply(current permissions.Permissions) (permissions.Permissions, error) {
+	for _, name := range p.Grant {
+		perms, ok := permissions.Lookup(name)
+		if !ok {
+			return current, fmt.Errorf("unknown permission %s", name)
+		}
+		current = current.Add(perms)
+	}
+	for _, name := range p.Revoke {
+		perms, ok := permissions.Lookup(name)
+		if !ok {
+			return current, fmt.Errorf("unknown permission %s", name)
+		}
+		current = current.Remove(perms)
+	}
+	return current, nil
+}
+
+type UserPermissions struct {
+	UserID      string                    `json:"userID"`
+	Permissions permissions.Permissions   `json:"permissions"`
+	Roles       []string                  `json:"roles"`
+	Actions     []string                  `json:"actions"`
+	Sources     []models.PermissionSource `json:"sources"`
+}
+
+func NewUserPermissions(user models.CompleteUser) UserPermissions {
+	perms := user.Permissions()
+	return UserPermissions{
+		UserID:      user.ID,
+		Permissions: perms,
+		Roles:       perms.Names("roles/"),
+		Actions:     perms.Names("actions/"),
+		Sources:     user.PermissionSources(),
 	}
 }
@@ -9,13 +9,15 @@ type ImageResponse struct {
 }
 
 type ReplayRequestPayload struct {
-	URL string `json:"url"`
+	URL    string `json:"url"`
+	Locale string `json:"locale"`
 }
 
 type PeriodRequestPayload struct {
 	Days       int        `json:"days"`
 	Presets    [][]string `json:"presets"`
 	Highlights []string   `json:"highlights"`
+	Locale     string     `json:"locale"`
 }
 
 type SessionRequestPayload struct {
@@ -29,6 +31,7 @@ type SessionRequestPayload struct {
 
 	Presets []string `json:"presets"`
 	TypeStr string   `json:"type"`
+	Locale  string   `json:"locale"`
 }
 
 func (p SessionRequestPayload) Type() models.SessionType {
@@ -18,40 +18,42 @@ type User struct {
 	Restrictions        []UserRestriction `json:"restrictions"`
 }
 
-type UserRestriction struct {
-	CreatedAt   time.Time `json:"createdAt"`
-	ExpiresAt   time.Time `json:"expiresAt"`
-	UserMessage string    `json:"userMessage"`
-	Comment     string    `json:"comment"`
-	Scopes      []string  `json:"scopes"`
-}
+type UserRestriction = models.UserRestriction
 
 func (u User) ActiveRestriction(scopes ...string) *UserRestriction {
-	now := time.Now()
 	for _, restriction := range u.Restrictions {
-		if restriction.ExpiresAt.After(now) {
-			if len(restriction.Scopes) == 0 {
-				return &restriction
-			}
-
-			for _, providedScope := range scopes {
-				for _, restrictionScope := range restriction.Scopes {
-					if restrictionScope == providedScope {
-						return &restriction
-					}
-				}
-			}
-
+		if restriction.Applies(scopes...) {
+			return &restriction
 		}
 	}
 	return nil
 }
 
+/*
+WargamingConnection returns the default Wargaming account linked to this user
+*/
 func (u User) WargamingConnection() (*wargamingConnection, bool) {
 	connection := u.Connection(models.ConnectionTypeWargaming)
 	if connection == nil {
 		return nil, false
 	}
+	return newWargamingConnection(*connection)
+}
+
+/*
+WargamingConnections returns all valid Wargaming accounts linked to this user, each with its own verification status
+*/
+func (u User) WargamingConnections() []wargamingConnection {
+	var connections []wargamingConnection
+	for _, connection := range u.ConnectionsByType(models.ConnectionTypeWargaming) {
+		if c, ok := newWargamingConnection(connection); ok {
+			connections = append(connections, *c)
+		}
+	}
+	return connections
+}
+
+func newWargamingConnection(connection models.UserConnection) (*wargamingConnection, bool) {
 	id, err := strconv.Atoi(connection.ExternalID)
 	if err != nil {
 		return nil, false
@@ -61,6 +63,7 @@ func (u User) WargamingConnection() (*wargamingConnection, bool) {
 	wargamingConnection.AccountID = id
 	wargamingConnection.Verified, _ = connection.Metadata["verified"].(bool)
 	wargamingConnection.Realm = utils.RealmFromPlayerID(id)
+	wargamingConnection.Default = connection.Default
 	return &wargamingConnection, true
 }
 
@@ -68,6 +71,7 @@ type wargamingConnection struct {
 	AccountID int    `json:"account_id"`
 	Verified  bool   `json:"verified"`
 	Realm     string `json:"realm"`
+	Default   bool   `json:"default"`
 }
 
 type UserContentPayload[T any] struct {
@@ -75,12 +79,16 @@ type UserContentPayload[T any] struct {
 }
 
 type UserVerificationPayload struct {
-	AccessTokenExpiresAt int64  `json:"access_token_expires_at"` // Not used
-	AccessToken          string `json:"access_token"`            // Not used
+	AccessTokenExpiresAt int64  `json:"access_token_expires_at"` // Unix timestamp
+	AccessToken          string `json:"access_token"`
 
 	AccountID string `json:"account_id"`
 }
 
+func (p UserVerificationPayload) Expired() bool {
+	return !time.Unix(p.AccessTokenExpiresAt, 0).After(time.Now())
+}
+
 type UserSubscriptionPayload struct {
 	UserID      string        `json:"user_id"`
 	ReferenceID string        `json:"reference_id"`